@@ -0,0 +1,146 @@
+package prismasdk2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// ListAPIAuthorizationPolicies returns all the API authorization policies of the client namespace
+func (t *Client) ListAPIAuthorizationPolicies(ctx context.Context) ([]*types.APIAuthorizationPolicy, error) {
+
+	t.logger.Debug("entering ListAPIAuthorizationPolicies")
+
+	ctx = telemetry.WithOperation(ctx, "ListAPIAuthorizationPolicies")
+
+	var result []*types.APIAuthorizationPolicy
+
+	iter := t.IterateAPIAuthorizationPolicies()
+	for iter.Next(ctx) {
+		result = append(result, iter.Item())
+	}
+
+	err := iter.Err()
+	if err != nil {
+		t.logger.Debug("returning ListAPIAuthorizationPolicies with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug(fmt.Sprintf("received %d API authorization policies for namespace %s", len(result), t.namespacePath))
+
+	t.logger.Debug("returning ListAPIAuthorizationPolicies")
+	return result, nil
+}
+
+// IterateAPIAuthorizationPolicies returns an iterator over the API authorization policies of the client
+// namespace. The iterator fetches one page at a time.
+func (t *Client) IterateAPIAuthorizationPolicies() *APIAuthorizationPolicyIterator {
+	return &APIAuthorizationPolicyIterator{
+		pager: t.newPager("IterateAPIAuthorizationPolicies", "/apiauthorizationpolicies", nil, nil),
+	}
+}
+
+// GetAPIAuthorizationPolicy returns the API authorization policy with the specified ID
+func (t *Client) GetAPIAuthorizationPolicy(ctx context.Context, id string) (*types.APIAuthorizationPolicy, error) {
+
+	t.logger.Debug("entering GetAPIAuthorizationPolicy")
+
+	ctx = telemetry.WithOperation(ctx, "GetAPIAuthorizationPolicy")
+
+	if id == "" {
+		t.logger.Debug("returning GetAPIAuthorizationPolicy with error(s)")
+		return nil, fmt.Errorf("id is required")
+	}
+
+	var result *types.APIAuthorizationPolicy
+
+	err := t.doJSON(ctx, "GET", "/apiauthorizationpolicies/"+id, nil, &result)
+	if err != nil {
+		t.logger.Debug("returning GetAPIAuthorizationPolicy with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning GetAPIAuthorizationPolicy")
+	return result, nil
+}
+
+// CreateAPIAuthorizationPolicy creates an API authorization policy in the client namespace and returns
+// the created policy. The returned policy has the server populated attributes such as the ID set.
+func (t *Client) CreateAPIAuthorizationPolicy(ctx context.Context, policy *types.APIAuthorizationPolicy) (*types.APIAuthorizationPolicy, error) {
+
+	t.logger.Debug("entering CreateAPIAuthorizationPolicy")
+
+	ctx = telemetry.WithOperation(ctx, "CreateAPIAuthorizationPolicy")
+
+	if policy.Name == "" {
+		t.logger.Debug("returning CreateAPIAuthorizationPolicy with error(s)")
+		return nil, fmt.Errorf("name is required")
+	}
+
+	var result *types.APIAuthorizationPolicy
+
+	err := t.doJSON(ctx, "POST", "/apiauthorizationpolicies", policy, &result)
+	if err != nil {
+		t.logger.Debug("returning CreateAPIAuthorizationPolicy with error(s)")
+		return nil, err
+	}
+
+	t.logger.Info(fmt.Sprintf("APIAuthorizationPolicy %s created with ID %s", result.Name, result.ID))
+
+	t.logger.Debug("returning CreateAPIAuthorizationPolicy")
+	return result, nil
+}
+
+// UpdateAPIAuthorizationPolicy updates an existing API authorization policy and returns the updated
+// policy. The ID of the policy must be set.
+func (t *Client) UpdateAPIAuthorizationPolicy(ctx context.Context, policy *types.APIAuthorizationPolicy) (*types.APIAuthorizationPolicy, error) {
+
+	t.logger.Debug("entering UpdateAPIAuthorizationPolicy")
+
+	ctx = telemetry.WithOperation(ctx, "UpdateAPIAuthorizationPolicy")
+
+	if policy.ID == "" {
+		t.logger.Debug("returning UpdateAPIAuthorizationPolicy with error(s)")
+		return nil, fmt.Errorf("policy is missing ID")
+	}
+
+	var result *types.APIAuthorizationPolicy
+
+	err := t.doJSON(ctx, "PUT", "/apiauthorizationpolicies/"+policy.ID, policy, &result)
+	if err != nil {
+		t.logger.Debug("returning UpdateAPIAuthorizationPolicy with error(s)")
+		return nil, err
+	}
+
+	t.logger.Info(fmt.Sprintf("APIAuthorizationPolicy %s updated", policy.ID))
+
+	t.logger.Debug("returning UpdateAPIAuthorizationPolicy")
+	return result, nil
+}
+
+// DeleteAPIAuthorizationPolicy deletes the API authorization policy with the specified ID. If the
+// policy is successfully deleted a nil error will be returned.
+func (t *Client) DeleteAPIAuthorizationPolicy(ctx context.Context, id string) error {
+
+	t.logger.Debug("entering DeleteAPIAuthorizationPolicy")
+
+	ctx = telemetry.WithOperation(ctx, "DeleteAPIAuthorizationPolicy")
+
+	if id == "" {
+		t.logger.Debug("returning DeleteAPIAuthorizationPolicy with error(s)")
+		return fmt.Errorf("id is required")
+	}
+
+	err := t.doJSON(ctx, "DELETE", "/apiauthorizationpolicies/"+id, nil, nil)
+	if err != nil {
+		t.logger.Debug("returning DeleteAPIAuthorizationPolicy with error(s)")
+		return err
+	}
+
+	t.logger.Info(fmt.Sprintf("APIAuthorizationPolicy %s deleted", id))
+
+	t.logger.Debug("returning DeleteAPIAuthorizationPolicy")
+	return nil
+}
@@ -0,0 +1,74 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func TestAPIAuthorizationPolicyRoundTrip(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant/team")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	ctx := context.Background()
+
+	created, err := client.CreateAPIAuthorizationPolicy(ctx, types.NewAPIAuthorizationPolicy("team").
+		AddSubject("@auth:realm=oidc", "@auth:group=team").
+		AddAuthorizedIdentity(types.RoleNamespaceViewer).
+		AddPermission(types.IdentityExternalNetwork, types.OperationGet, types.OperationPut).
+		SetAuthorizedNamespace("/tenant/team"))
+	if err != nil {
+		t.Fatalf("CreateAPIAuthorizationPolicy: %v", err)
+	}
+
+	if created.ID == "" || created.Namespace != "/tenant" || created.CreateTime == nil || created.UpdateTime == nil {
+		t.Errorf("server populated fields are missing from %+v", created)
+	}
+
+	got, err := client.GetAPIAuthorizationPolicy(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetAPIAuthorizationPolicy: %v", err)
+	}
+
+	if got.Name != "team" || got.AuthorizedNamespace != "/tenant/team" ||
+		len(got.AuthorizedIdentities) != 2 || got.AuthorizedIdentities[0] != "@auth:role=namespace.viewer" ||
+		got.AuthorizedIdentities[1] != "externalnetwork:get,put" {
+		t.Errorf("GetAPIAuthorizationPolicy returned %+v", got)
+	}
+
+	got.AddAuthorizedIdentity(types.RoleNamespaceEditor)
+
+	updated, err := client.UpdateAPIAuthorizationPolicy(ctx, got)
+	if err != nil {
+		t.Fatalf("UpdateAPIAuthorizationPolicy: %v", err)
+	}
+
+	if updated.ID != created.ID || len(updated.AuthorizedIdentities) != 3 ||
+		updated.AuthorizedIdentities[2] != "@auth:role=namespace.editor" {
+		t.Errorf("UpdateAPIAuthorizationPolicy returned %+v", updated)
+	}
+
+	policies, err := client.ListAPIAuthorizationPolicies(ctx)
+	if err != nil {
+		t.Fatalf("ListAPIAuthorizationPolicies: %v", err)
+	}
+
+	if len(policies) != 1 || len(policies[0].AuthorizedIdentities) != 3 {
+		t.Errorf("ListAPIAuthorizationPolicies returned %d policies, want the updated policy", len(policies))
+	}
+
+	err = client.DeleteAPIAuthorizationPolicy(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("DeleteAPIAuthorizationPolicy: %v", err)
+	}
+
+	_, err = client.GetAPIAuthorizationPolicy(ctx, created.ID)
+	if statusCode(err) != 404 {
+		t.Errorf("GetAPIAuthorizationPolicy of a deleted policy returned %v, want 404", err)
+	}
+}
@@ -0,0 +1,168 @@
+package prismasdk2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// ListAppCredentials returns all the app credentials of the client namespace. The credential files
+// are not returned.
+func (t *Client) ListAppCredentials(ctx context.Context) ([]*types.AppCredential, error) {
+
+	t.logger.Debug("entering ListAppCredentials")
+
+	ctx = telemetry.WithOperation(ctx, "ListAppCredentials")
+
+	var result []*types.AppCredential
+
+	iter := t.IterateAppCredentials()
+	for iter.Next(ctx) {
+		result = append(result, iter.Item())
+	}
+
+	err := iter.Err()
+	if err != nil {
+		t.logger.Debug("returning ListAppCredentials with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug(fmt.Sprintf("received %d app credentials for namespace %s", len(result), t.namespacePath))
+
+	t.logger.Debug("returning ListAppCredentials")
+	return result, nil
+}
+
+// IterateAppCredentials returns an iterator over the app credentials of the client namespace. The
+// iterator fetches one page at a time.
+func (t *Client) IterateAppCredentials() *AppCredentialIterator {
+	return &AppCredentialIterator{
+		pager: t.newPager("IterateAppCredentials", "/appcredentials", nil, nil),
+	}
+}
+
+// GetAppCredential returns the app credential with the specified ID. The credential file is not
+// returned.
+func (t *Client) GetAppCredential(ctx context.Context, id string) (*types.AppCredential, error) {
+
+	t.logger.Debug("entering GetAppCredential")
+
+	ctx = telemetry.WithOperation(ctx, "GetAppCredential")
+
+	if id == "" {
+		t.logger.Debug("returning GetAppCredential with error(s)")
+		return nil, fmt.Errorf("id is required")
+	}
+
+	var result *types.AppCredential
+
+	err := t.doJSON(ctx, "GET", "/appcredentials/"+id, nil, &result)
+	if err != nil {
+		t.logger.Debug("returning GetAppCredential with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning GetAppCredential")
+	return result, nil
+}
+
+// CreateAppCredential creates an app credential in the client namespace and returns its credential
+// file. The credential file holds the private key and is only returned here and by
+// RotateAppCredential; it can be written with WriteFile or passed to the appcred token provider.
+func (t *Client) CreateAppCredential(ctx context.Context, appCredential *types.AppCredential) (*types.Credential, error) {
+
+	t.logger.Debug("entering CreateAppCredential")
+
+	ctx = telemetry.WithOperation(ctx, "CreateAppCredential")
+
+	if appCredential.Name == "" {
+		t.logger.Debug("returning CreateAppCredential with error(s)")
+		return nil, fmt.Errorf("name is required")
+	}
+
+	if len(appCredential.Roles) == 0 {
+		t.logger.Debug("returning CreateAppCredential with error(s)")
+		return nil, fmt.Errorf("at least one role is required")
+	}
+
+	var result *types.AppCredential
+
+	err := t.doJSON(ctx, "POST", "/appcredentials", appCredential, &result)
+	if err != nil {
+		t.logger.Debug("returning CreateAppCredential with error(s)")
+		return nil, err
+	}
+
+	if result.Credentials == nil {
+		t.logger.Debug("returning CreateAppCredential with error(s)")
+		return nil, fmt.Errorf("app credential %s created with ID %s but no credential file was returned", result.Name, result.ID)
+	}
+
+	t.logger.Info(fmt.Sprintf("AppCredential %s created with ID %s", result.Name, result.ID))
+
+	t.logger.Debug("returning CreateAppCredential")
+	return result.Credentials, nil
+}
+
+// RotateAppCredential regenerates the certificate of the app credential with the specified ID and
+// returns the new credential file. The previous credential file can no longer be used.
+func (t *Client) RotateAppCredential(ctx context.Context, id string) (*types.Credential, error) {
+
+	t.logger.Debug("entering RotateAppCredential")
+
+	ctx = telemetry.WithOperation(ctx, "RotateAppCredential")
+
+	appCredential, err := t.GetAppCredential(ctx, id)
+	if err != nil {
+		t.logger.Debug("returning RotateAppCredential with error(s)")
+		return nil, err
+	}
+
+	// Updating an app credential without a CSR makes the server generate a new certificate
+	appCredential.Credentials = nil
+
+	var result *types.AppCredential
+
+	err = t.doJSON(ctx, "PUT", "/appcredentials/"+id, appCredential, &result)
+	if err != nil {
+		t.logger.Debug("returning RotateAppCredential with error(s)")
+		return nil, err
+	}
+
+	if result.Credentials == nil {
+		t.logger.Debug("returning RotateAppCredential with error(s)")
+		return nil, fmt.Errorf("app credential %s was updated but no credential file was returned", id)
+	}
+
+	t.logger.Info(fmt.Sprintf("AppCredential %s rotated", id))
+
+	t.logger.Debug("returning RotateAppCredential")
+	return result.Credentials, nil
+}
+
+// DeleteAppCredential deletes the app credential with the specified ID. Its credential file can no
+// longer be used. If the app credential is successfully deleted a nil error will be returned.
+func (t *Client) DeleteAppCredential(ctx context.Context, id string) error {
+
+	t.logger.Debug("entering DeleteAppCredential")
+
+	ctx = telemetry.WithOperation(ctx, "DeleteAppCredential")
+
+	if id == "" {
+		t.logger.Debug("returning DeleteAppCredential with error(s)")
+		return fmt.Errorf("id is required")
+	}
+
+	err := t.doJSON(ctx, "DELETE", "/appcredentials/"+id, nil, nil)
+	if err != nil {
+		t.logger.Debug("returning DeleteAppCredential with error(s)")
+		return err
+	}
+
+	t.logger.Info(fmt.Sprintf("AppCredential %s deleted", id))
+
+	t.logger.Debug("returning DeleteAppCredential")
+	return nil
+}
@@ -0,0 +1,99 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"testing"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_appcred "github.com/aporeto-se/prisma-sdk-go-v2/token/appcred"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// issue returns the error of issuing a token with credential
+func issue(credential *types.Credential) error {
+
+	tokenProvider, err := token_appcred.NewConfig().
+		SetCredential(credential).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = tokenProvider.Token(context.Background())
+	return err
+}
+
+func TestAppCredentialRoundTrip(t *testing.T) {
+
+	// The credential files are only accepted over TLS
+	server := prismatest.NewTLSServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, prisma_api.NewConfig().SetHTTPClient(server.Client()))
+
+	ctx := context.Background()
+
+	credential, err := client.CreateAppCredential(ctx, types.NewAppCredential("service").
+		AddRole(types.RoleNamespaceViewer).
+		AddAuthorizedSubnet("10.0.0.0/8"))
+	if err != nil {
+		t.Fatalf("CreateAppCredential: %v", err)
+	}
+
+	if credential.ID == "" || credential.Name != "service" || credential.Namespace != "/tenant" ||
+		credential.Certificate == "" || credential.CertificateKey == "" || credential.CertificateAuthority == "" {
+		t.Errorf("CreateAppCredential returned an incomplete credential file %+v", credential)
+	}
+
+	if err := issue(credential); err != nil {
+		t.Errorf("Token with the created credential file: %v", err)
+	}
+
+	appCredentials, err := client.ListAppCredentials(ctx)
+	if err != nil {
+		t.Fatalf("ListAppCredentials: %v", err)
+	}
+
+	if len(appCredentials) != 1 || appCredentials[0].ID != credential.ID ||
+		len(appCredentials[0].Roles) != 1 || appCredentials[0].Roles[0] != "@auth:role=namespace.viewer" ||
+		len(appCredentials[0].AuthorizedSubnets) != 1 {
+		t.Fatalf("ListAppCredentials returned %d app credentials, want the created one", len(appCredentials))
+	}
+
+	// The credential file is not returned once the app credential is created
+	if appCredentials[0].Credentials != nil {
+		t.Errorf("ListAppCredentials returned the credential file")
+	}
+
+	rotated, err := client.RotateAppCredential(ctx, credential.ID)
+	if err != nil {
+		t.Fatalf("RotateAppCredential: %v", err)
+	}
+
+	if rotated.ID != credential.ID || rotated.Certificate == credential.Certificate {
+		t.Errorf("RotateAppCredential returned the previous certificate")
+	}
+
+	if err := issue(credential); err == nil {
+		t.Errorf("Token with the rotated credential file returned no error")
+	}
+
+	if err := issue(rotated); err != nil {
+		t.Errorf("Token with the new credential file: %v", err)
+	}
+
+	err = client.DeleteAppCredential(ctx, credential.ID)
+	if err != nil {
+		t.Fatalf("DeleteAppCredential: %v", err)
+	}
+
+	if err := issue(rotated); err == nil {
+		t.Errorf("Token with the credential file of a deleted app credential returned no error")
+	}
+
+	_, err = client.GetAppCredential(ctx, credential.ID)
+	if statusCode(err) != 404 {
+		t.Errorf("GetAppCredential of a deleted app credential returned %v, want 404", err)
+	}
+}
@@ -1,11 +1,8 @@
 package prismasdk2
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"strings"
 	"sync"
@@ -14,6 +11,8 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"go.uber.org/zap"
 
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
 	"github.com/aporeto-se/prisma-sdk-go-v2/types"
 )
 
@@ -22,16 +21,23 @@ type Client struct {
 	api           string
 	namespacePath string
 	TokenProvider
-	httpClient *http.Client
-	namespace  *types.Namespace
-	namespaces []*types.Namespace
-	mutex      sync.Mutex
+	httpClient      *http.Client
+	handler         Handler
+	retryPolicy     *retry.Policy
+	pageSize        int
+	logger          *zap.Logger
+	instrumentation *telemetry.Instrumentation
+	namespace       *types.Namespace
+	namespaces      []*types.Namespace
+	mutex           sync.Mutex
 }
 
 // NewClient returns new Client
 func NewClient(ctx context.Context, config *Config) (*Client, error) {
 
-	zap.L().Debug("entering NewClient")
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
 
 	var errors *multierror.Error
 
@@ -49,7 +55,7 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 
 	err := errors.ErrorOrNil()
 	if err != nil {
-		zap.L().Debug("returning NewClient with error(s)")
+		logger.Debug("returning NewClient with error(s)")
 		return nil, err
 	}
 
@@ -57,16 +63,29 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 
 	if httpClient == nil {
 		httpClient = &http.Client{}
-		zap.L().Debug("HTTPClient created new")
+		logger.Debug("HTTPClient created new")
 	} else {
-		zap.L().Debug("HTTPClient set from config")
+		logger.Debug("HTTPClient set from config")
+	}
+
+	retryPolicy := config.RetryPolicy
+
+	if retryPolicy == nil {
+		retryPolicy = retry.NewPolicy()
 	}
 
+	retryPolicy = retryPolicy.WithLogger(logger)
+
 	client := &Client{
-		api:           config.API,
-		namespacePath: config.Namespace,
-		TokenProvider: config.TokenProvider,
-		httpClient:    config.HTTPClient,
+		api:             config.API,
+		namespacePath:   config.Namespace,
+		TokenProvider:   config.TokenProvider,
+		httpClient:      httpClient,
+		handler:         chain(httpClient.Do, config.Middlewares...),
+		retryPolicy:     retryPolicy,
+		pageSize:        config.PageSize,
+		logger:          logger,
+		instrumentation: telemetry.New(config.TracerProvider, config.Metrics),
 		namespace: &types.Namespace{
 			Name:          basename(config.Namespace),
 			NamespaceType: types.NamespaceTypeUndefined,
@@ -78,7 +97,7 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 		return nil, err
 	}
 
-	zap.L().Debug("returning NewClient")
+	logger.Debug("returning NewClient")
 	return client, nil
 }
 
@@ -102,6 +121,9 @@ func namespaceResToNamespace(namespace *namespaceRes) *types.Namespace {
 		DefaultPUIncomingTrafficAction: ingressTrafficAction,
 		DefaultPUOutgoingTrafficAction: egressTrafficAction,
 		ID:                             namespace.ID,
+		Description:                    namespace.Description,
+		AssociatedTags:                 namespace.AssociatedTags,
+		TagPrefixes:                    namespace.TagPrefixes,
 		Annotations:                    namespace.Annotations,
 	}
 }
@@ -130,7 +152,7 @@ type namespaceRes struct {
 	OrganizationalMetadata         []string            `json:"organizationalMetadata"`
 	Protected                      bool                `json:"protected"`
 	ServiceCertificateValidity     string              `json:"serviceCertificateValidity"`
-	TagPrefixes                    []interface{}       `json:"tagPrefixes"`
+	TagPrefixes                    []string            `json:"tagPrefixes"`
 	Type                           string              `json:"type"`
 	UpdateTime                     time.Time           `json:"updateTime"`
 	Zoning                         int                 `json:"zoning"`
@@ -140,105 +162,96 @@ type namespaceRes struct {
 // When a child namespace is to be manipulated you must get the child directly from its parent
 func (t *Client) NewClient(ctx context.Context, name string) (*Client, error) {
 
-	zap.L().Debug("entering NewClient")
+	t.logger.Debug("entering NewClient")
 
 	namespace, err := t.GetNamespace(name)
 	if err != nil {
-		zap.L().Debug("returning NewClient with error(s)")
+		t.logger.Debug("returning NewClient with error(s)")
 		return nil, err
 	}
 
-	client := &Client{
-		api:           t.api,
-		namespacePath: t.namespacePath + "/" + namespace.Name,
-		TokenProvider: t.TokenProvider,
-		httpClient:    t.httpClient,
-		namespace:     namespace,
-	}
+	client := t.child(namespace)
 
 	err = client.SyncNamespaces(ctx)
 	if err != nil {
-		zap.L().Debug("returning NewClient with error(s)")
+		t.logger.Debug("returning NewClient with error(s)")
 		return nil, err
 	}
 
-	zap.L().Debug("returning NewClient")
+	t.logger.Debug("returning NewClient")
 	return client, nil
 }
 
+// child returns a client for the child namespace sharing the settings of this client. The
+// children of the child namespace are not synced.
+func (t *Client) child(namespace *types.Namespace) *Client {
+	return &Client{
+		api:             t.api,
+		namespacePath:   t.namespacePath + "/" + namespace.Name,
+		TokenProvider:   t.TokenProvider,
+		httpClient:      t.httpClient,
+		handler:         t.handler,
+		retryPolicy:     t.retryPolicy,
+		pageSize:        t.pageSize,
+		logger:          t.logger,
+		instrumentation: t.instrumentation,
+		namespace:       namespace,
+	}
+}
+
 // SyncNamespaces fetches the child namespaces of a namespace. It is called automatically when the
 // client is created. If a new namespace is created or deleted from the client this client will be
 // aware. But if the change is made from outside of this client then we will not be aware. Calling
 // this will cause us to (re)sync the namespaces.
 func (t *Client) SyncNamespaces(ctx context.Context) error {
 
-	zap.L().Debug("entering SyncNamespaces")
-
-	token, err := t.Token(ctx)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "GET", t.api+"/namespaces", nil)
-	if err != nil {
-		zap.L().Debug("returning SyncNamespaces with HTTP Request error(s)")
-		return err
-	}
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
+	t.logger.Debug("entering SyncNamespaces")
 
-	req.Header.Add("X-Namespace", t.namespacePath)
-	req.Header.Add("Authorization", "Bearer "+token)
+	ctx = telemetry.WithOperation(ctx, "SyncNamespaces")
 
-	req.Header.Add("X-Fields", "name")
-	req.Header.Add("X-Fields", "ID")
-	req.Header.Add("X-Fields", "defaultPUIncomingTrafficAction")
-	req.Header.Add("X-Fields", "defaultPUOutgoingTrafficAction")
-	req.Header.Add("X-Fields", "description")
-	req.Header.Add("X-Fields", "annotations")
+	var namespaces []*types.Namespace
 
-	resp, err := t.httpClient.Do(req)
-	if err != nil {
-		zap.L().Debug("returning SyncNamespaces with HTTP Response error(s)")
-		return err
+	iter := t.IterateNamespaces()
+	for iter.Next(ctx) {
+		namespaces = append(namespaces, iter.Item())
 	}
 
-	defer resp.Body.Close()
-	bytes, err := ioutil.ReadAll(resp.Body)
+	err := iter.Err()
 	if err != nil {
-		zap.L().Debug("returning SyncNamespaces with IO error(s)")
+		t.logger.Debug("returning SyncNamespaces with error(s)")
 		return err
 	}
 
-	if resp.StatusCode != 200 {
-		zap.L().Debug("returning SyncNamespaces with StatusCode error(s)")
-		return types.NewAPIError(bytes)
-	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
 
-	var raw []*namespaceRes
-	err = json.Unmarshal(bytes, &raw)
+	t.namespaces = namespaces
 
-	if err != nil {
-		zap.L().Debug("returning SyncNamespaces with JSON Unmarshal error(s)")
-		return err
-	}
+	t.logger.Debug(fmt.Sprintf("received %d children for namespace %s", len(t.namespaces), t.namespacePath))
 
-	var namespaces []*types.Namespace
+	t.logger.Debug("returning SyncNamespaces")
+	return nil
+}
 
-	for i := range raw {
-		e := raw[i]
-		namespaces = append(namespaces, namespaceResToNamespace(e))
+// IterateNamespaces returns an iterator over the child namespaces of the client namespace. The
+// iterator fetches one page at a time. Unlike GetNamespaces the result is not cached.
+func (t *Client) IterateNamespaces() *NamespaceIterator {
+	return &NamespaceIterator{
+		pager: t.newPager("IterateNamespaces", "/namespaces", nil, namespaceFieldsHeader()),
 	}
+}
 
-	t.namespaces = namespaces
-
-	zap.L().Debug(fmt.Sprintf("received %d children for namespace %s", len(t.namespaces), t.namespacePath))
-
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
-
-	zap.L().Debug("returning SyncNamespaces")
-	return nil
+func namespaceFieldsHeader() http.Header {
+	header := http.Header{}
+	header.Add("X-Fields", "name")
+	header.Add("X-Fields", "ID")
+	header.Add("X-Fields", "defaultPUIncomingTrafficAction")
+	header.Add("X-Fields", "defaultPUOutgoingTrafficAction")
+	header.Add("X-Fields", "description")
+	header.Add("X-Fields", "annotations")
+	header.Add("X-Fields", "associatedTags")
+	header.Add("X-Fields", "tagPrefixes")
+	return header
 }
 
 type namespaceDataReq struct {
@@ -246,7 +259,9 @@ type namespaceDataReq struct {
 	DefaultPUIncomingTrafficAction string              `json:"defaultPUIncomingTrafficAction"`
 	DefaultPUOutgoingTrafficAction string              `json:"defaultPUOutgoingTrafficAction"`
 	Name                           string              `json:"name"`
-	AssociatedTags                 []string            `yaml:"associatedTags"`
+	Description                    string              `json:"description,omitempty"`
+	AssociatedTags                 []string            `json:"associatedTags,omitempty" yaml:"associatedTags"`
+	TagPrefixes                    []string            `json:"tagPrefixes,omitempty"`
 	Annotations                    map[string][]string `json:"annotations" yaml:"annotations"`
 }
 
@@ -254,72 +269,45 @@ type namespaceDataReq struct {
 // input namespace and the returned namespace is the presence of the ID.
 func (t *Client) CreateNamespace(ctx context.Context, namespace *types.Namespace) (*types.Namespace, error) {
 
-	zap.L().Debug("entering CreateNamespace")
+	t.logger.Debug("entering CreateNamespace")
+
+	ctx = telemetry.WithOperation(ctx, "CreateNamespace")
 
 	existingNamespace := t.getNamespace(namespace.Name)
 	if existingNamespace != nil {
-		zap.L().Debug(fmt.Sprintf("returning CreateNamespace (%s already exist)", namespace.Name))
+		t.logger.Debug(fmt.Sprintf("returning CreateNamespace (%s already exist)", namespace.Name))
 		return existingNamespace, nil
 	}
 
-	zap.L().Debug(fmt.Sprintf("Namespace %s does not exist; will be created", namespace.Name))
+	t.logger.Debug(fmt.Sprintf("Namespace %s does not exist; will be created", namespace.Name))
 
-	token, err := t.Token(ctx)
-	if err != nil {
-		return nil, err
-	}
+	var raw *namespaceRes
 
-	j, _ := json.Marshal(&namespaceDataReq{
-		Name:                           namespace.Name,
-		Group:                          string(namespace.NamespaceType),
-		DefaultPUIncomingTrafficAction: string(namespace.DefaultPUIncomingTrafficAction),
-		DefaultPUOutgoingTrafficAction: string(namespace.DefaultPUOutgoingTrafficAction),
-		Annotations:                    namespace.Annotations,
+	_, err := t.execute(ctx, &request{
+		method: "POST",
+		path:   "/namespaces",
+		header: namespaceFieldsHeader(),
+		in: &namespaceDataReq{
+			Name:                           namespace.Name,
+			Group:                          string(namespace.NamespaceType),
+			DefaultPUIncomingTrafficAction: string(namespace.DefaultPUIncomingTrafficAction),
+			DefaultPUOutgoingTrafficAction: string(namespace.DefaultPUOutgoingTrafficAction),
+			Description:                    namespace.Description,
+			AssociatedTags:                 namespace.AssociatedTags,
+			TagPrefixes:                    namespace.TagPrefixes,
+			Annotations:                    namespace.Annotations,
+		},
+		out: &raw,
 	})
 
-	req, err := http.NewRequestWithContext(ctx, "POST", t.api+"/namespaces", bytes.NewBuffer(j))
-	if err != nil {
-		zap.L().Debug("returning CreateNamespace with HTTP Request error(s)")
-		return nil, err
-	}
-
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
-
-	req.Header.Add("X-Namespace", t.namespacePath)
-	req.Header.Add("Authorization", "Bearer "+token)
-	req.Header.Add("X-Fields", "name")
-	req.Header.Add("X-Fields", "ID")
-	req.Header.Add("X-Fields", "defaultPUIncomingTrafficAction")
-	req.Header.Add("X-Fields", "defaultPUOutgoingTrafficAction")
-	req.Header.Add("X-Fields", "description")
-	req.Header.Add("X-Fields", "annotations")
-
-	resp, err := t.httpClient.Do(req)
 	if err != nil {
-		zap.L().Debug("returning CreateNamespace with HTTP Response error(s)")
+		t.logger.Debug("returning CreateNamespace with error(s)")
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	bytes, err := ioutil.ReadAll(resp.Body)
-
-	if err != nil {
-		zap.L().Debug("returning CreateNamespace with IO error(s)")
-		return nil, err
-	}
-
-	if resp.StatusCode != 200 {
-		zap.L().Debug("returning CreateNamespace with StatusCode error(s)")
-		return nil, types.NewAPIError(bytes)
-	}
-
-	var raw *namespaceRes
-	err = json.Unmarshal(bytes, &raw)
-
-	if err != nil {
-		zap.L().Debug("returning CreateNamespace with JSON Unmarshal error(s)")
-		return nil, err
+	if raw == nil {
+		t.logger.Debug("returning CreateNamespace with error(s)")
+		return nil, fmt.Errorf("namespace %s was created but no namespace was returned", namespace.Name)
 	}
 
 	namespace = namespaceResToNamespace(raw)
@@ -328,9 +316,9 @@ func (t *Client) CreateNamespace(ctx context.Context, namespace *types.Namespace
 	defer t.mutex.Unlock()
 	t.namespaces = append(t.namespaces, namespace)
 
-	zap.L().Info(fmt.Sprintf("Namespace %s created with ID %s", namespace.Name, namespace.ID))
+	t.logger.Info(fmt.Sprintf("Namespace %s created with ID %s", namespace.Name, namespace.ID))
 
-	zap.L().Debug("returning CreateNamespace")
+	t.logger.Debug("returning CreateNamespace")
 	return namespace, nil
 }
 
@@ -338,65 +326,39 @@ func (t *Client) CreateNamespace(ctx context.Context, namespace *types.Namespace
 // a nil error will be returned.
 func (t *Client) DeleteNamespace(ctx context.Context, name string) error {
 
-	zap.L().Debug("entering DeleteNamespace")
+	t.logger.Debug("entering DeleteNamespace")
 
-	token, err := t.Token(ctx)
-	if err != nil {
-		zap.L().Debug("returning DeleteNamespace with error(s)")
-		return err
-	}
+	ctx = telemetry.WithOperation(ctx, "DeleteNamespace")
 
 	namespace, err := t.GetNamespace(name)
 	if err != nil {
-		zap.L().Debug("returning DeleteNamespace with error(s)")
+		t.logger.Debug("returning DeleteNamespace with error(s)")
 		return err
 	}
 
 	if namespace.ID == "" {
-		zap.L().Debug("returning DeleteNamespace with missing ID error")
+		t.logger.Debug("returning DeleteNamespace with missing ID error")
 		return fmt.Errorf("namespace is missing ID")
 	}
 
-	//TODO
-	req, err := http.NewRequestWithContext(ctx, "DELETE", t.api+"/namespaces/"+namespace.ID, nil)
-	if err != nil {
-		zap.L().Debug("returning DeleteNamespace with HTTP Request error(s)")
-		return err
-	}
-
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("X-Namespace", t.namespacePath)
-	req.Header.Add("Authorization", "Bearer "+token)
-
-	resp, err := t.httpClient.Do(req)
+	err = t.doJSON(ctx, "DELETE", "/namespaces/"+namespace.ID, nil, nil)
 	if err != nil {
-		zap.L().Debug("returning DeleteNamespace with HTTP Response error(s)")
+		t.logger.Debug("returning DeleteNamespace with error(s)")
 		return err
 	}
 
-	defer resp.Body.Close()
-	bytes, err := ioutil.ReadAll(resp.Body)
-
-	if err != nil {
-		zap.L().Debug("returning DeleteNamespace with IO error(s)")
-		return err
-	}
-
-	switch resp.StatusCode {
-	case 200:
-		break
-	case 204:
-		break
-
-	default:
-		zap.L().Debug("returning DeleteNamespace with error(s)")
-		return types.NewAPIError(bytes)
+	t.mutex.Lock()
+	for i, v := range t.namespaces {
+		if v.ID == namespace.ID {
+			t.namespaces = append(t.namespaces[:i], t.namespaces[i+1:]...)
+			break
+		}
 	}
+	t.mutex.Unlock()
 
-	zap.L().Info(fmt.Sprintf("Namespace %s deleted", name))
+	t.logger.Info(fmt.Sprintf("Namespace %s deleted", name))
 
-	zap.L().Debug("returning DeleteNamespace")
+	t.logger.Debug("returning DeleteNamespace")
 	return nil
 }
 
@@ -419,16 +381,16 @@ func (t *Client) GetNamespaces() []*types.Namespace {
 // GetNamespace returns the child namespace of the current client by name
 func (t *Client) GetNamespace(name string) (*types.Namespace, error) {
 
-	zap.L().Debug("entering GetNamespace")
+	t.logger.Debug("entering GetNamespace")
 
 	result := t.getNamespace(name)
 
 	if result != nil {
-		zap.L().Debug("returning GetNamespace")
+		t.logger.Debug("returning GetNamespace")
 		return result, nil
 	}
 
-	zap.L().Debug("returning GetNamespace with error(s)")
+	t.logger.Debug("returning GetNamespace with error(s)")
 
 	return nil, &types.APIError{
 		Code:        404,
@@ -452,102 +414,73 @@ func (t *Client) getNamespace(name string) *types.Namespace {
 // HasNamespace returns true if the current client namespace has the child by name
 func (t *Client) HasNamespace(name string) bool {
 
-	zap.L().Debug("entering HasNamespace")
+	t.logger.Debug("entering HasNamespace")
 
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
 	for _, v := range t.namespaces {
 		if v.Name == name {
-			zap.L().Debug("returning HasNamespace with true")
+			t.logger.Debug("returning HasNamespace with true")
 			return true
 		}
 	}
 
-	zap.L().Debug("returning HasNamespace with false")
+	t.logger.Debug("returning HasNamespace with false")
 	return false
 }
 
 // ImportPrismaConfig imports config into the currently client namespace
 func (t *Client) ImportPrismaConfig(ctx context.Context, prismaConfig *types.PrismaConfig) error {
 
-	zap.L().Debug("entering ImportPrismaConfig")
+	t.logger.Debug("entering ImportPrismaConfig")
 
-	token, err := t.Token(ctx)
-	if err != nil {
-		zap.L().Debug("returning ImportPrismaConfig with error(s)")
-		return err
-	}
+	ctx = telemetry.WithOperation(ctx, "ImportPrismaConfig")
 
 	if prismaConfig.Label == "" {
-		zap.L().Debug("returning ImportPrismaConfig with error(s)")
+		t.logger.Debug("returning ImportPrismaConfig with error(s)")
 		return fmt.Errorf("name is required")
 	}
 
 	if len(prismaConfig.Data.Apiauthorizationpolicies) > 0 {
-		prismaConfig.Identities = append(prismaConfig.Identities, "apiauthorizationpolicy")
+		prismaConfig.Identities = appendIdentity(prismaConfig.Identities, "apiauthorizationpolicy")
 	}
 
 	if len(prismaConfig.Data.Externalnetworks) > 0 {
-		prismaConfig.Identities = append(prismaConfig.Identities, "externalnetwork")
+		prismaConfig.Identities = appendIdentity(prismaConfig.Identities, "externalnetwork")
 	}
 
 	if len(prismaConfig.Data.Networkrulesetpolicies) > 0 {
-		prismaConfig.Identities = append(prismaConfig.Identities, "networkrulesetpolicy")
+		prismaConfig.Identities = appendIdentity(prismaConfig.Identities, "networkrulesetpolicy")
 	}
 
-	zap.L().Debug(fmt.Sprintf("ImportPrismaConfig: namespace=%s, label=%s : start", t.namespacePath, prismaConfig.Label))
+	t.logger.Debug(fmt.Sprintf("ImportPrismaConfig: namespace=%s, label=%s : start", t.namespacePath, prismaConfig.Label))
 
 	p := &types.PrismaConfigOuter{
 		Data: prismaConfig,
 	}
 
-	j, err := json.Marshal(p)
-
+	err := t.doJSON(ctx, "POST", "/import", p, nil)
 	if err != nil {
-		zap.L().Debug("returning ImportPrismaConfig with JSON Marshal error(s)")
+		t.logger.Debug("returning ImportPrismaConfig with error(s)")
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", t.api+"/import", bytes.NewBuffer(j))
-	if err != nil {
-		zap.L().Debug("returning ImportPrismaConfig with HTTP Request error(s)")
-		return err
-	}
-
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("X-Namespace", t.namespacePath)
-	req.Header.Add("Authorization", "Bearer "+token)
+	t.logger.Info(fmt.Sprintf("PrismaConfig imported into namespace %s", t.namespacePath))
 
-	resp, err := t.httpClient.Do(req)
-	if err != nil {
-		zap.L().Debug("returning ImportPrismaConfig with HTTP Response error(s)")
-		return err
-	}
-
-	defer resp.Body.Close()
-	bytes, err := ioutil.ReadAll(resp.Body)
-
-	if err != nil {
-		zap.L().Debug("returning ImportPrismaConfig with IO error(s)")
-		return err
-	}
-
-	switch resp.StatusCode {
-	case 200:
-		break
-	case 204:
-		break
+	t.logger.Debug("returning ImportPrismaConfig")
+	return nil
+}
 
-	default:
-		return types.NewAPIError(bytes)
+// appendIdentity appends identity to identities unless it is already present. This allows a
+// PrismaConfig returned by Export to be imported again.
+func appendIdentity(identities []string, identity string) []string {
+	for _, v := range identities {
+		if v == identity {
+			return identities
+		}
 	}
-
-	zap.L().Info(fmt.Sprintf("PrismaConfig imported into namespace %s", t.namespacePath))
-
-	zap.L().Debug("returning ImportPrismaConfig")
-	return nil
+	return append(identities, identity)
 }
 
 // // AccountID returns Cloud Account ID or error
@@ -555,7 +488,7 @@ func (t *Client) ImportPrismaConfig(ctx context.Context, prismaConfig *types.Pri
 
 // 	result, err := t.TokenProvider.AccountID(ctx)
 // 	if err != nil {
-// 		zap.L().Debug("returning AccountID with error(s)")
+// 		t.logger.Debug("returning AccountID with error(s)")
 // 		return "", err
 // 	}
 
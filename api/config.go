@@ -3,14 +3,26 @@ package prismasdk2
 import (
 	"context"
 	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
 )
 
 // Config config
 type Config struct {
-	API           string
-	Namespace     string
-	HTTPClient    *http.Client
-	TokenProvider TokenProvider
+	API            string
+	Namespace      string
+	HTTPClient     *http.Client
+	TokenProvider  TokenProvider
+	PageSize       int
+	RetryPolicy    *retry.Policy
+	Middlewares    []Middleware
+	Logger         *zap.Logger
+	TracerProvider trace.TracerProvider
+	Metrics        telemetry.Metrics
 }
 
 // NewConfig returns new Config
@@ -42,6 +54,58 @@ func (t *Config) SetHTTPClient(v *http.Client) *Config {
 	return t
 }
 
+// SetPageSize sets the number of objects requested per page when listing and returns self. If not
+// set DefaultPageSize is used.
+func (t *Config) SetPageSize(v int) *Config {
+	t.PageSize = v
+	return t
+}
+
+// SetRetryPolicy sets entity and returns self. If not set retry.NewPolicy() is used. Use
+// retry.NoRetry() to disable retries.
+func (t *Config) SetRetryPolicy(v *retry.Policy) *Config {
+	t.RetryPolicy = v
+	return t
+}
+
+// Use appends middlewares to the request pipeline and returns self. The middlewares are called in
+// the order they are added for every request sent by the client and its children.
+func (t *Config) Use(v ...Middleware) *Config {
+	t.Middlewares = append(t.Middlewares, v...)
+	return t
+}
+
+// SetLogger sets entity and returns self. The client and its children log to the logger. If not
+// set nothing is logged.
+func (t *Config) SetLogger(v *zap.Logger) *Config {
+	t.Logger = v
+	return t
+}
+
+// GetLogger returns entity. If entity is nil a no-op logger is returned.
+func (t *Config) GetLogger() *zap.Logger {
+
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+
+	return t.Logger
+}
+
+// SetTracerProvider sets interface and returns self. Every API call is wrapped in a span named
+// after the operation. If not set nothing is traced.
+func (t *Config) SetTracerProvider(v trace.TracerProvider) *Config {
+	t.TracerProvider = v
+	return t
+}
+
+// SetMetrics sets interface and returns self. See the telemetry/prometheus module for a
+// Prometheus implementation. If not set nothing is measured.
+func (t *Config) SetMetrics(v telemetry.Metrics) *Config {
+	t.Metrics = v
+	return t
+}
+
 // Build returns entity
 func (t *Config) Build(ctx context.Context) (*Client, error) {
 	return NewClient(ctx, t)
@@ -0,0 +1,131 @@
+package prismasdk2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// DefaultExportIdentities are the identities exported when none are specified. These are the
+// identities modeled by types.PrismaConfig.
+var DefaultExportIdentities = []string{
+	"apiauthorizationpolicy",
+	"externalnetwork",
+	"networkrulesetpolicy",
+}
+
+// ExportConfig config
+type ExportConfig struct {
+	Identities []string
+	Label      string
+	Recursive  bool
+}
+
+// NewExportConfig returns new ExportConfig
+func NewExportConfig() *ExportConfig {
+	return &ExportConfig{}
+}
+
+// AddIdentities adds identities and returns self
+func (t *ExportConfig) AddIdentities(v ...string) *ExportConfig {
+	t.Identities = append(t.Identities, v...)
+	return t
+}
+
+// SetLabel sets attribute and returns self. If set only objects imported with this label are
+// returned.
+func (t *ExportConfig) SetLabel(v string) *ExportConfig {
+	t.Label = v
+	return t
+}
+
+// SetRecursive sets attribute and returns self. If true objects of the child namespaces are
+// exported as well.
+func (t *ExportConfig) SetRecursive(v bool) *ExportConfig {
+	t.Recursive = v
+	return t
+}
+
+type exportReq struct {
+	Identities []string `json:"identities"`
+	Label      string   `json:"label,omitempty"`
+}
+
+// ExportPrismaConfig exports the specified identities of the client namespace. If no identities
+// are specified DefaultExportIdentities are exported.
+func (t *Client) ExportPrismaConfig(ctx context.Context, identities ...string) (*types.PrismaConfig, error) {
+	return t.Export(ctx, NewExportConfig().AddIdentities(identities...))
+}
+
+// Export exports the client namespace as specified by config
+func (t *Client) Export(ctx context.Context, config *ExportConfig) (*types.PrismaConfig, error) {
+
+	t.logger.Debug("entering Export")
+
+	ctx = telemetry.WithOperation(ctx, "Export")
+
+	identities := config.Identities
+	if len(identities) == 0 {
+		identities = DefaultExportIdentities
+	}
+
+	path := "/export"
+	if config.Recursive {
+		path = path + "?recursive=true"
+	}
+
+	var result *types.PrismaConfig
+
+	err := t.doJSON(ctx, "POST", path, &exportReq{
+		Identities: identities,
+		Label:      config.Label,
+	}, &result)
+
+	if err != nil {
+		t.logger.Debug("returning Export with error(s)")
+		return nil, err
+	}
+
+	if result == nil {
+		result = &types.PrismaConfig{}
+	}
+
+	if config.Label != "" {
+		filterImportLabel(result, config.Label)
+		result.Label = config.Label
+	}
+
+	t.logger.Debug(fmt.Sprintf("Export: namespace=%s, identities=%v, recursive=%t", t.namespacePath, identities, config.Recursive))
+
+	t.logger.Debug("returning Export")
+	return result, nil
+}
+
+func filterImportLabel(prismaConfig *types.PrismaConfig, label string) {
+
+	var apiauthorizationpolicies []*types.APIAuthorizationPolicy
+	for _, v := range prismaConfig.Data.Apiauthorizationpolicies {
+		if v.ImportLabel == label {
+			apiauthorizationpolicies = append(apiauthorizationpolicies, v)
+		}
+	}
+	prismaConfig.Data.Apiauthorizationpolicies = apiauthorizationpolicies
+
+	var externalnetworks []*types.Externalnetwork
+	for _, v := range prismaConfig.Data.Externalnetworks {
+		if v.ImportLabel == label {
+			externalnetworks = append(externalnetworks, v)
+		}
+	}
+	prismaConfig.Data.Externalnetworks = externalnetworks
+
+	var networkrulesetpolicies []*types.Networkrulesetpolicy
+	for _, v := range prismaConfig.Data.Networkrulesetpolicies {
+		if v.ImportLabel == label {
+			networkrulesetpolicies = append(networkrulesetpolicies, v)
+		}
+	}
+	prismaConfig.Data.Networkrulesetpolicies = networkrulesetpolicies
+}
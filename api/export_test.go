@@ -0,0 +1,126 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"testing"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func importNetwork(t *testing.T, client *prisma_api.Client, label, name string) {
+
+	t.Helper()
+
+	err := client.ImportPrismaConfig(context.Background(), types.NewPrismaConfig(label).
+		AddExternalnetwork(types.NewExternalnetwork(name).AddEntry("10.0.0.0/8")))
+	if err != nil {
+		t.Fatalf("ImportPrismaConfig: %v", err)
+	}
+}
+
+func names(prismaConfig *types.PrismaConfig) map[string]bool {
+	result := make(map[string]bool)
+	for _, v := range prismaConfig.Data.Externalnetworks {
+		result[v.Name] = true
+	}
+	return result
+}
+
+func TestExportLabel(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant/child")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	child, err := client.NewClient(context.Background(), "child")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	importNetwork(t, client, "team-a", "a")
+	importNetwork(t, client, "team-b", "b")
+	importNetwork(t, child, "team-a", "child-a")
+
+	_, err = client.CreateExternalNetwork(context.Background(), types.NewExternalnetwork("unlabeled"))
+	if err != nil {
+		t.Fatalf("CreateExternalNetwork: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config *prisma_api.ExportConfig
+		want   []string
+	}{
+		{"all", prisma_api.NewExportConfig(), []string{"a", "b", "unlabeled"}},
+		{"label", prisma_api.NewExportConfig().SetLabel("team-a"), []string{"a"}},
+		{"label recursive", prisma_api.NewExportConfig().SetLabel("team-a").SetRecursive(true), []string{"a", "child-a"}},
+		{"unknown label", prisma_api.NewExportConfig().SetLabel("team-c"), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			prismaConfig, err := client.Export(context.Background(), test.config)
+			if err != nil {
+				t.Fatalf("Export: %v", err)
+			}
+
+			got := names(prismaConfig)
+
+			if len(got) != len(test.want) {
+				t.Errorf("Export returned the networks %v, want %v", got, test.want)
+			}
+
+			for _, v := range test.want {
+				if !got[v] {
+					t.Errorf("Export returned the networks %v, want %v", got, test.want)
+				}
+			}
+
+			if prismaConfig.Label != test.config.Label {
+				t.Errorf("Export returned the label %q, want %q", prismaConfig.Label, test.config.Label)
+			}
+		})
+	}
+}
+
+func TestExportPrismaConfigRoundTrip(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	importNetwork(t, client, "team-a", "a")
+
+	prismaConfig, err := client.ExportPrismaConfig(context.Background(), "externalnetwork")
+	if err != nil {
+		t.Fatalf("ExportPrismaConfig: %v", err)
+	}
+
+	networks := prismaConfig.Data.Externalnetworks
+
+	if len(networks) != 1 || networks[0].Name != "a" || networks[0].ImportLabel != "team-a" || networks[0].ID == "" {
+		t.Fatalf("ExportPrismaConfig returned %d networks, want the imported network", len(networks))
+	}
+
+	// The exported config can be imported again under its label
+	prismaConfig.Label = "team-a"
+
+	err = client.ImportPrismaConfig(context.Background(), prismaConfig)
+	if err != nil {
+		t.Fatalf("ImportPrismaConfig of the exported config: %v", err)
+	}
+
+	networks, err = client.ListExternalNetworks(context.Background())
+	if err != nil {
+		t.Fatalf("ListExternalNetworks: %v", err)
+	}
+
+	if len(networks) != 1 || networks[0].Name != "a" {
+		t.Errorf("got %d networks after the import of the exported config, want 1", len(networks))
+	}
+}
@@ -0,0 +1,146 @@
+package prismasdk2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// ListExternalNetworks returns all the external networks of the client namespace
+func (t *Client) ListExternalNetworks(ctx context.Context) ([]*types.Externalnetwork, error) {
+
+	t.logger.Debug("entering ListExternalNetworks")
+
+	ctx = telemetry.WithOperation(ctx, "ListExternalNetworks")
+
+	var result []*types.Externalnetwork
+
+	iter := t.IterateExternalNetworks()
+	for iter.Next(ctx) {
+		result = append(result, iter.Item())
+	}
+
+	err := iter.Err()
+	if err != nil {
+		t.logger.Debug("returning ListExternalNetworks with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug(fmt.Sprintf("received %d external networks for namespace %s", len(result), t.namespacePath))
+
+	t.logger.Debug("returning ListExternalNetworks")
+	return result, nil
+}
+
+// IterateExternalNetworks returns an iterator over the external networks of the client
+// namespace. The iterator fetches one page at a time.
+func (t *Client) IterateExternalNetworks() *ExternalNetworkIterator {
+	return &ExternalNetworkIterator{
+		pager: t.newPager("IterateExternalNetworks", "/externalnetworks", nil, nil),
+	}
+}
+
+// GetExternalNetwork returns the external network with the specified ID
+func (t *Client) GetExternalNetwork(ctx context.Context, id string) (*types.Externalnetwork, error) {
+
+	t.logger.Debug("entering GetExternalNetwork")
+
+	ctx = telemetry.WithOperation(ctx, "GetExternalNetwork")
+
+	if id == "" {
+		t.logger.Debug("returning GetExternalNetwork with error(s)")
+		return nil, fmt.Errorf("id is required")
+	}
+
+	var result *types.Externalnetwork
+
+	err := t.doJSON(ctx, "GET", "/externalnetworks/"+id, nil, &result)
+	if err != nil {
+		t.logger.Debug("returning GetExternalNetwork with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning GetExternalNetwork")
+	return result, nil
+}
+
+// CreateExternalNetwork creates a external network in the client namespace and returns
+// the created external network. The returned external network has the server populated attributes such as the ID set.
+func (t *Client) CreateExternalNetwork(ctx context.Context, network *types.Externalnetwork) (*types.Externalnetwork, error) {
+
+	t.logger.Debug("entering CreateExternalNetwork")
+
+	ctx = telemetry.WithOperation(ctx, "CreateExternalNetwork")
+
+	if network.Name == "" {
+		t.logger.Debug("returning CreateExternalNetwork with error(s)")
+		return nil, fmt.Errorf("name is required")
+	}
+
+	var result *types.Externalnetwork
+
+	err := t.doJSON(ctx, "POST", "/externalnetworks", network, &result)
+	if err != nil {
+		t.logger.Debug("returning CreateExternalNetwork with error(s)")
+		return nil, err
+	}
+
+	t.logger.Info(fmt.Sprintf("Externalnetwork %s created with ID %s", result.Name, result.ID))
+
+	t.logger.Debug("returning CreateExternalNetwork")
+	return result, nil
+}
+
+// UpdateExternalNetwork updates an existing external network and returns the updated
+// external network. The ID of the external network must be set.
+func (t *Client) UpdateExternalNetwork(ctx context.Context, network *types.Externalnetwork) (*types.Externalnetwork, error) {
+
+	t.logger.Debug("entering UpdateExternalNetwork")
+
+	ctx = telemetry.WithOperation(ctx, "UpdateExternalNetwork")
+
+	if network.ID == "" {
+		t.logger.Debug("returning UpdateExternalNetwork with error(s)")
+		return nil, fmt.Errorf("external network is missing ID")
+	}
+
+	var result *types.Externalnetwork
+
+	err := t.doJSON(ctx, "PUT", "/externalnetworks/"+network.ID, network, &result)
+	if err != nil {
+		t.logger.Debug("returning UpdateExternalNetwork with error(s)")
+		return nil, err
+	}
+
+	t.logger.Info(fmt.Sprintf("Externalnetwork %s updated", network.ID))
+
+	t.logger.Debug("returning UpdateExternalNetwork")
+	return result, nil
+}
+
+// DeleteExternalNetwork deletes the external network with the specified ID. If the
+// external network is successfully deleted a nil error will be returned.
+func (t *Client) DeleteExternalNetwork(ctx context.Context, id string) error {
+
+	t.logger.Debug("entering DeleteExternalNetwork")
+
+	ctx = telemetry.WithOperation(ctx, "DeleteExternalNetwork")
+
+	if id == "" {
+		t.logger.Debug("returning DeleteExternalNetwork with error(s)")
+		return fmt.Errorf("id is required")
+	}
+
+	err := t.doJSON(ctx, "DELETE", "/externalnetworks/"+id, nil, nil)
+	if err != nil {
+		t.logger.Debug("returning DeleteExternalNetwork with error(s)")
+		return err
+	}
+
+	t.logger.Info(fmt.Sprintf("Externalnetwork %s deleted", id))
+
+	t.logger.Debug("returning DeleteExternalNetwork")
+	return nil
+}
@@ -0,0 +1,69 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func TestExternalNetworkRoundTrip(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	ctx := context.Background()
+
+	created, err := client.CreateExternalNetwork(ctx, types.NewExternalnetwork("dns").
+		AddEntry("10.0.0.53/32"))
+	if err != nil {
+		t.Fatalf("CreateExternalNetwork: %v", err)
+	}
+
+	if created.ID == "" || created.Namespace != "/tenant" || created.CreateTime == nil || created.UpdateTime == nil {
+		t.Errorf("server populated fields are missing from %+v", created)
+	}
+
+	got, err := client.GetExternalNetwork(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetExternalNetwork: %v", err)
+	}
+
+	if got.Name != "dns" || len(got.Entries) != 1 || got.Entries[0] != "10.0.0.53/32" {
+		t.Errorf("GetExternalNetwork returned %+v", got)
+	}
+
+	// Only the entries of the network are changed
+	got.SetEntries([]string{"10.0.1.53/32"})
+
+	updated, err := client.UpdateExternalNetwork(ctx, got)
+	if err != nil {
+		t.Fatalf("UpdateExternalNetwork: %v", err)
+	}
+
+	if updated.ID != created.ID || len(updated.Entries) != 1 || updated.Entries[0] != "10.0.1.53/32" {
+		t.Errorf("UpdateExternalNetwork returned %+v", updated)
+	}
+
+	networks, err := client.ListExternalNetworks(ctx)
+	if err != nil {
+		t.Fatalf("ListExternalNetworks: %v", err)
+	}
+
+	if len(networks) != 1 || networks[0].Entries[0] != "10.0.1.53/32" {
+		t.Errorf("ListExternalNetworks returned %d networks, want the updated network", len(networks))
+	}
+
+	err = client.DeleteExternalNetwork(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("DeleteExternalNetwork: %v", err)
+	}
+
+	_, err = client.GetExternalNetwork(ctx, created.ID)
+	if statusCode(err) != 404 {
+		t.Errorf("GetExternalNetwork of a deleted network returned %v, want 404", err)
+	}
+}
@@ -0,0 +1,32 @@
+package prismasdk2
+
+import (
+	"net/http"
+)
+
+// Handler sends a single request to the Prisma API and returns the response
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler. A Middleware can inspect or modify the request before calling
+// next, inspect the response after, or return a response without calling next at all. The
+// chain is called once per attempt so a Middleware sees each retry.
+type Middleware func(next Handler) Handler
+
+// chain returns a Handler that calls the middlewares in order before calling handler. The first
+// middleware is the outermost.
+func chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// WithHeader returns a Middleware that sets the header key to value on every request
+func WithHeader(key, value string) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set(key, value)
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// newEmptyBodyProxy returns a proxy that drops the body of the responses to requests with method
+// for the namespaces
+func newEmptyBodyProxy(t *testing.T, server *prismatest.Server, method string) *httptest.Server {
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		if resp.Request.Method == method && strings.HasPrefix(resp.Request.URL.Path, "/namespaces") {
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(strings.NewReader(""))
+			resp.ContentLength = 0
+			resp.Header.Del("Content-Length")
+		}
+		return nil
+	}
+
+	return httptest.NewServer(proxy)
+}
+
+func TestCreateNamespaceEmptyResponse(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	proxy := newEmptyBodyProxy(t, server, "POST")
+	defer proxy.Close()
+
+	client := newTestClient(t, proxy.URL, server, nil)
+
+	_, err := client.CreateNamespace(context.Background(), &types.Namespace{Name: "a"})
+	if err == nil || !strings.Contains(err.Error(), "no namespace was returned") {
+		t.Errorf("CreateNamespace returned %v, want an error for the empty response", err)
+	}
+}
+
+func TestUpdateNamespaceEmptyResponse(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant/a")
+	defer server.Close()
+
+	proxy := newEmptyBodyProxy(t, server, "PUT")
+	defer proxy.Close()
+
+	client := newTestClient(t, proxy.URL, server, nil)
+
+	_, err := client.UpdateNamespace(context.Background(), &types.Namespace{Name: "a", Description: "updated"})
+	if err == nil || !strings.Contains(err.Error(), "no namespace was returned") {
+		t.Errorf("UpdateNamespace returned %v, want an error for the empty response", err)
+	}
+}
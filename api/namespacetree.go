@@ -0,0 +1,238 @@
+package prismasdk2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// SkipChildren can be returned by the WalkFunc to skip the children of the current node
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called by NamespaceTree.Walk for each node
+type WalkFunc func(node *NamespaceNode) error
+
+// NamespaceNode is a namespace within a NamespaceTree
+type NamespaceNode struct {
+	Namespace *types.Namespace
+	Path      string
+	Depth     int
+	Parent    *NamespaceNode
+	Children  []*NamespaceNode
+}
+
+// Child returns the direct child by name or nil if it does not exist
+func (t *NamespaceNode) Child(name string) *NamespaceNode {
+	for _, v := range t.Children {
+		if v.Namespace.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// NamespaceTree is the namespace hierarchy below the client namespace. The root of the tree is
+// the client namespace itself at depth 0.
+type NamespaceTree struct {
+	Root  *NamespaceNode
+	nodes map[string]*NamespaceNode
+}
+
+// NamespaceTree returns the namespaces below the client namespace as a tree. If maxDepth is 0 or
+// less the whole hierarchy is loaded with a single recursive query. If maxDepth is greater than 0
+// the tree is loaded one level at a time and only namespaces up to maxDepth levels below the
+// client namespace are fetched; this takes one query per namespace above the last level.
+func (t *Client) NamespaceTree(ctx context.Context, maxDepth int) (*NamespaceTree, error) {
+
+	t.logger.Debug("entering NamespaceTree")
+
+	ctx = telemetry.WithOperation(ctx, "NamespaceTree")
+
+	tree := &NamespaceTree{
+		Root: &NamespaceNode{
+			Namespace: t.namespace,
+			Path:      t.namespacePath,
+		},
+		nodes: make(map[string]*NamespaceNode),
+	}
+
+	tree.nodes[t.namespacePath] = tree.Root
+
+	var err error
+
+	if maxDepth > 0 {
+		err = tree.loadLevels(ctx, t, maxDepth)
+	} else {
+		err = tree.load(ctx, t, true)
+	}
+
+	if err != nil {
+		t.logger.Debug("returning NamespaceTree with error(s)")
+		return nil, err
+	}
+
+	tree.Walk(func(node *NamespaceNode) error {
+		sort.Slice(node.Children, func(i, j int) bool {
+			return node.Children[i].Namespace.Name < node.Children[j].Namespace.Name
+		})
+		return nil
+	})
+
+	t.logger.Debug(fmt.Sprintf("received %d namespaces below namespace %s", len(tree.nodes)-1, t.namespacePath))
+
+	t.logger.Debug("returning NamespaceTree")
+	return tree, nil
+}
+
+// loadLevels loads the children of each namespace level by level down to maxDepth
+func (t *NamespaceTree) loadLevels(ctx context.Context, client *Client, maxDepth int) error {
+
+	clients := map[*NamespaceNode]*Client{t.Root: client}
+	level := []*NamespaceNode{t.Root}
+
+	for depth := 1; depth <= maxDepth && len(level) > 0; depth++ {
+
+		var next []*NamespaceNode
+
+		for _, node := range level {
+
+			c := clients[node]
+
+			err := t.load(ctx, c, false)
+			if err != nil {
+				return err
+			}
+
+			for _, child := range node.Children {
+				clients[child] = c.child(child.Namespace)
+				next = append(next, child)
+			}
+		}
+
+		level = next
+	}
+
+	return nil
+}
+
+// load adds the namespaces below the namespace of client to the tree
+func (t *NamespaceTree) load(ctx context.Context, client *Client, recursive bool) error {
+
+	var query url.Values
+
+	if recursive {
+		query = url.Values{}
+		query.Set("recursive", "true")
+	}
+
+	p := client.newPager("NamespaceTree", "/namespaces", query, namespaceFieldsHeader())
+
+	for {
+
+		var raw *namespaceRes
+		if !p.next(ctx, &raw) {
+			break
+		}
+
+		path := raw.Name
+		if !strings.HasPrefix(path, "/") {
+			path = client.namespacePath + "/" + path
+		}
+
+		if !strings.HasPrefix(path, client.namespacePath+"/") {
+			continue
+		}
+
+		t.insert(path, namespaceResToNamespace(raw))
+	}
+
+	return p.err
+}
+
+// insert adds the namespace at path creating placeholder nodes for missing parents
+func (t *NamespaceTree) insert(path string, namespace *types.Namespace) *NamespaceNode {
+
+	node, ok := t.nodes[path]
+	if ok {
+		if namespace != nil {
+			node.Namespace = namespace
+		}
+		return node
+	}
+
+	if namespace == nil {
+		namespace = types.NewNamespace(basename(path))
+	}
+
+	parent := t.insert(path[:strings.LastIndex(path, "/")], nil)
+
+	node = &NamespaceNode{
+		Namespace: namespace,
+		Path:      path,
+		Depth:     parent.Depth + 1,
+		Parent:    parent,
+	}
+
+	parent.Children = append(parent.Children, node)
+	t.nodes[path] = node
+
+	return node
+}
+
+// Walk calls fn for each node of the tree starting at the root, parents before children. If fn
+// returns SkipChildren the children of that node are skipped. Any other error stops the walk and
+// is returned.
+func (t *NamespaceTree) Walk(fn WalkFunc) error {
+	err := walk(t.Root, fn)
+	if err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+func walk(node *NamespaceNode, fn WalkFunc) error {
+
+	err := fn(node)
+	if err == SkipChildren {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, child := range node.Children {
+		err = walk(child, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Find returns the node by path or nil if it does not exist. The path may be absolute or relative
+// to the root of the tree.
+func (t *NamespaceTree) Find(path string) *NamespaceNode {
+
+	path = strings.TrimSuffix(path, "/")
+
+	if !strings.HasPrefix(path, "/") {
+		if path == "" {
+			return t.Root
+		}
+		path = t.Root.Path + "/" + path
+	}
+
+	return t.nodes[path]
+}
+
+// Len returns the number of nodes in the tree including the root
+func (t *NamespaceTree) Len() int {
+	return len(t.nodes)
+}
@@ -0,0 +1,93 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+)
+
+func TestNamespaceTree(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant/a/x/deep", "/tenant/a/y", "/tenant/b")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	tests := []struct {
+		maxDepth   int
+		paths      []string
+		namespaces []string
+	}{
+		{
+			maxDepth:   0,
+			paths:      []string{"/tenant", "/tenant/a", "/tenant/a/x", "/tenant/a/x/deep", "/tenant/a/y", "/tenant/b"},
+			namespaces: []string{"/tenant"},
+		},
+		{
+			maxDepth:   1,
+			paths:      []string{"/tenant", "/tenant/a", "/tenant/b"},
+			namespaces: []string{"/tenant"},
+		},
+		{
+			maxDepth:   2,
+			paths:      []string{"/tenant", "/tenant/a", "/tenant/a/x", "/tenant/a/y", "/tenant/b"},
+			namespaces: []string{"/tenant", "/tenant/a", "/tenant/b"},
+		},
+	}
+
+	for _, test := range tests {
+
+		before := len(server.Requests())
+
+		tree, err := client.NamespaceTree(context.Background(), test.maxDepth)
+		if err != nil {
+			t.Fatalf("NamespaceTree(%d): %v", test.maxDepth, err)
+		}
+
+		var paths []string
+		tree.Walk(func(node *prisma_api.NamespaceNode) error {
+			paths = append(paths, node.Path)
+			return nil
+		})
+
+		if !equal(paths, test.paths) {
+			t.Errorf("NamespaceTree(%d) has %v, want %v", test.maxDepth, paths, test.paths)
+		}
+
+		// Only the namespaces above the last level are queried
+		var namespaces []string
+		for _, v := range server.Requests()[before:] {
+			namespaces = append(namespaces, v.Namespace)
+		}
+		sort.Strings(namespaces)
+
+		if !equal(namespaces, test.namespaces) {
+			t.Errorf("NamespaceTree(%d) queried %v, want %v", test.maxDepth, namespaces, test.namespaces)
+		}
+	}
+
+	tree, err := client.NamespaceTree(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := tree.Find("a/x/deep")
+	if node == nil || node.Depth != 3 || node.Parent.Path != "/tenant/a/x" {
+		t.Errorf("Find returned %+v", node)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package prismasdk2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// namespaceUpdateReq is sparse; attributes that are not set are left unchanged by the API
+type namespaceUpdateReq struct {
+	Description                    string              `json:"description,omitempty"`
+	DefaultPUIncomingTrafficAction string              `json:"defaultPUIncomingTrafficAction,omitempty"`
+	DefaultPUOutgoingTrafficAction string              `json:"defaultPUOutgoingTrafficAction,omitempty"`
+	AssociatedTags                 []string            `json:"associatedTags,omitempty"`
+	TagPrefixes                    []string            `json:"tagPrefixes,omitempty"`
+	Annotations                    map[string][]string `json:"annotations,omitempty"`
+}
+
+func trafficActionReq(v types.TrafficAction) string {
+	if v == types.TrafficActionUndefined {
+		return ""
+	}
+	return string(v)
+}
+
+// UpdateNamespace updates the child namespace with the same name as namespace and returns the
+// updated namespace. Only the description, default PU traffic actions, annotations, associated
+// tags and tag prefixes are updated. Attributes that are empty (or TrafficActionUndefined) are
+// left unchanged. Annotations replace the existing annotations; use PatchNamespaceAnnotations to
+// merge them instead.
+func (t *Client) UpdateNamespace(ctx context.Context, namespace *types.Namespace) (*types.Namespace, error) {
+
+	t.logger.Debug("entering UpdateNamespace")
+
+	ctx = telemetry.WithOperation(ctx, "UpdateNamespace")
+
+	id, err := t.namespaceID(namespace)
+	if err != nil {
+		t.logger.Debug("returning UpdateNamespace with error(s)")
+		return nil, err
+	}
+
+	result, err := t.updateNamespace(ctx, id, &namespaceUpdateReq{
+		Description:                    namespace.Description,
+		DefaultPUIncomingTrafficAction: trafficActionReq(namespace.DefaultPUIncomingTrafficAction),
+		DefaultPUOutgoingTrafficAction: trafficActionReq(namespace.DefaultPUOutgoingTrafficAction),
+		AssociatedTags:                 namespace.AssociatedTags,
+		TagPrefixes:                    namespace.TagPrefixes,
+		Annotations:                    namespace.Annotations,
+	})
+
+	if err != nil {
+		t.logger.Debug("returning UpdateNamespace with error(s)")
+		return nil, err
+	}
+
+	t.logger.Info(fmt.Sprintf("Namespace %s updated", result.Name))
+
+	t.logger.Debug("returning UpdateNamespace")
+	return result, nil
+}
+
+// PatchNamespaceAnnotations merges annotations into the existing annotations of the child
+// namespace by name and returns the updated namespace. The current annotations are fetched from
+// the API before merging. Keys in annotations replace existing keys, keys with a nil value are
+// removed and all other existing keys are kept.
+func (t *Client) PatchNamespaceAnnotations(ctx context.Context, name string, annotations map[string][]string) (*types.Namespace, error) {
+
+	t.logger.Debug("entering PatchNamespaceAnnotations")
+
+	ctx = telemetry.WithOperation(ctx, "PatchNamespaceAnnotations")
+
+	id, err := t.namespaceID(types.NewNamespace(name))
+	if err != nil {
+		t.logger.Debug("returning PatchNamespaceAnnotations with error(s)")
+		return nil, err
+	}
+
+	var current *namespaceRes
+
+	err = t.doJSON(ctx, "GET", "/namespaces/"+id, nil, &current)
+	if err != nil {
+		t.logger.Debug("returning PatchNamespaceAnnotations with error(s)")
+		return nil, err
+	}
+
+	merged := make(map[string][]string)
+
+	for k, v := range current.Annotations {
+		merged[k] = v
+	}
+
+	for k, v := range annotations {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+
+	// An empty map would be omitted from the sparse request so removing the last key sends an
+	// explicit empty map instead
+	req := struct {
+		Annotations map[string][]string `json:"annotations"`
+	}{
+		Annotations: merged,
+	}
+
+	result, err := t.updateNamespace(ctx, id, &req)
+	if err != nil {
+		t.logger.Debug("returning PatchNamespaceAnnotations with error(s)")
+		return nil, err
+	}
+
+	t.logger.Info(fmt.Sprintf("Namespace %s annotations patched", result.Name))
+
+	t.logger.Debug("returning PatchNamespaceAnnotations")
+	return result, nil
+}
+
+// namespaceID returns the ID of the namespace or the ID of the cached child with the same name
+func (t *Client) namespaceID(namespace *types.Namespace) (string, error) {
+
+	if namespace.ID != "" {
+		return namespace.ID, nil
+	}
+
+	existing, err := t.GetNamespace(namespace.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if existing.ID == "" {
+		return "", fmt.Errorf("namespace is missing ID")
+	}
+
+	return existing.ID, nil
+}
+
+func (t *Client) updateNamespace(ctx context.Context, id string, req interface{}) (*types.Namespace, error) {
+
+	var raw *namespaceRes
+
+	err := t.doJSON(ctx, "PUT", "/namespaces/"+id, req, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw == nil {
+		return nil, fmt.Errorf("namespace with ID %s was updated but no namespace was returned", id)
+	}
+
+	namespace := namespaceResToNamespace(raw)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for i, v := range t.namespaces {
+		if v.ID == id {
+			t.namespaces[i] = namespace
+		}
+	}
+
+	return namespace, nil
+}
@@ -0,0 +1,146 @@
+package prismasdk2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// ListNetworkRuleSetPolicies returns all the network rule set policies of the client namespace
+func (t *Client) ListNetworkRuleSetPolicies(ctx context.Context) ([]*types.Networkrulesetpolicy, error) {
+
+	t.logger.Debug("entering ListNetworkRuleSetPolicies")
+
+	ctx = telemetry.WithOperation(ctx, "ListNetworkRuleSetPolicies")
+
+	var result []*types.Networkrulesetpolicy
+
+	iter := t.IterateNetworkRuleSetPolicies()
+	for iter.Next(ctx) {
+		result = append(result, iter.Item())
+	}
+
+	err := iter.Err()
+	if err != nil {
+		t.logger.Debug("returning ListNetworkRuleSetPolicies with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug(fmt.Sprintf("received %d network rule set policies for namespace %s", len(result), t.namespacePath))
+
+	t.logger.Debug("returning ListNetworkRuleSetPolicies")
+	return result, nil
+}
+
+// IterateNetworkRuleSetPolicies returns an iterator over the network rule set policies of the client
+// namespace. The iterator fetches one page at a time.
+func (t *Client) IterateNetworkRuleSetPolicies() *NetworkRuleSetPolicyIterator {
+	return &NetworkRuleSetPolicyIterator{
+		pager: t.newPager("IterateNetworkRuleSetPolicies", "/networkrulesetpolicies", nil, nil),
+	}
+}
+
+// GetNetworkRuleSetPolicy returns the network rule set policy with the specified ID
+func (t *Client) GetNetworkRuleSetPolicy(ctx context.Context, id string) (*types.Networkrulesetpolicy, error) {
+
+	t.logger.Debug("entering GetNetworkRuleSetPolicy")
+
+	ctx = telemetry.WithOperation(ctx, "GetNetworkRuleSetPolicy")
+
+	if id == "" {
+		t.logger.Debug("returning GetNetworkRuleSetPolicy with error(s)")
+		return nil, fmt.Errorf("id is required")
+	}
+
+	var result *types.Networkrulesetpolicy
+
+	err := t.doJSON(ctx, "GET", "/networkrulesetpolicies/"+id, nil, &result)
+	if err != nil {
+		t.logger.Debug("returning GetNetworkRuleSetPolicy with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning GetNetworkRuleSetPolicy")
+	return result, nil
+}
+
+// CreateNetworkRuleSetPolicy creates a network rule set policy in the client namespace and returns
+// the created policy. The returned policy has the server populated attributes such as the ID set.
+func (t *Client) CreateNetworkRuleSetPolicy(ctx context.Context, policy *types.Networkrulesetpolicy) (*types.Networkrulesetpolicy, error) {
+
+	t.logger.Debug("entering CreateNetworkRuleSetPolicy")
+
+	ctx = telemetry.WithOperation(ctx, "CreateNetworkRuleSetPolicy")
+
+	if policy.Name == "" {
+		t.logger.Debug("returning CreateNetworkRuleSetPolicy with error(s)")
+		return nil, fmt.Errorf("name is required")
+	}
+
+	var result *types.Networkrulesetpolicy
+
+	err := t.doJSON(ctx, "POST", "/networkrulesetpolicies", policy, &result)
+	if err != nil {
+		t.logger.Debug("returning CreateNetworkRuleSetPolicy with error(s)")
+		return nil, err
+	}
+
+	t.logger.Info(fmt.Sprintf("Networkrulesetpolicy %s created with ID %s", result.Name, result.ID))
+
+	t.logger.Debug("returning CreateNetworkRuleSetPolicy")
+	return result, nil
+}
+
+// UpdateNetworkRuleSetPolicy updates an existing network rule set policy and returns the updated
+// policy. The ID of the policy must be set.
+func (t *Client) UpdateNetworkRuleSetPolicy(ctx context.Context, policy *types.Networkrulesetpolicy) (*types.Networkrulesetpolicy, error) {
+
+	t.logger.Debug("entering UpdateNetworkRuleSetPolicy")
+
+	ctx = telemetry.WithOperation(ctx, "UpdateNetworkRuleSetPolicy")
+
+	if policy.ID == "" {
+		t.logger.Debug("returning UpdateNetworkRuleSetPolicy with error(s)")
+		return nil, fmt.Errorf("policy is missing ID")
+	}
+
+	var result *types.Networkrulesetpolicy
+
+	err := t.doJSON(ctx, "PUT", "/networkrulesetpolicies/"+policy.ID, policy, &result)
+	if err != nil {
+		t.logger.Debug("returning UpdateNetworkRuleSetPolicy with error(s)")
+		return nil, err
+	}
+
+	t.logger.Info(fmt.Sprintf("Networkrulesetpolicy %s updated", policy.ID))
+
+	t.logger.Debug("returning UpdateNetworkRuleSetPolicy")
+	return result, nil
+}
+
+// DeleteNetworkRuleSetPolicy deletes the network rule set policy with the specified ID. If the
+// policy is successfully deleted a nil error will be returned.
+func (t *Client) DeleteNetworkRuleSetPolicy(ctx context.Context, id string) error {
+
+	t.logger.Debug("entering DeleteNetworkRuleSetPolicy")
+
+	ctx = telemetry.WithOperation(ctx, "DeleteNetworkRuleSetPolicy")
+
+	if id == "" {
+		t.logger.Debug("returning DeleteNetworkRuleSetPolicy with error(s)")
+		return fmt.Errorf("id is required")
+	}
+
+	err := t.doJSON(ctx, "DELETE", "/networkrulesetpolicies/"+id, nil, nil)
+	if err != nil {
+		t.logger.Debug("returning DeleteNetworkRuleSetPolicy with error(s)")
+		return err
+	}
+
+	t.logger.Info(fmt.Sprintf("Networkrulesetpolicy %s deleted", id))
+
+	t.logger.Debug("returning DeleteNetworkRuleSetPolicy")
+	return nil
+}
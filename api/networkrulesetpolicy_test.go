@@ -0,0 +1,69 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func TestNetworkRuleSetPolicyRoundTrip(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	ctx := context.Background()
+
+	created, err := client.CreateNetworkRuleSetPolicy(ctx, types.NewNetworkrulesetpolicy("allow-dns").
+		SetDescription("allow DNS").
+		AddSubject("$identity=processingunit"))
+	if err != nil {
+		t.Fatalf("CreateNetworkRuleSetPolicy: %v", err)
+	}
+
+	if created.ID == "" || created.Namespace != "/tenant" || created.CreateTime == nil || created.UpdateTime == nil {
+		t.Errorf("server populated fields are missing from %+v", created)
+	}
+
+	got, err := client.GetNetworkRuleSetPolicy(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetNetworkRuleSetPolicy: %v", err)
+	}
+
+	if got.Name != "allow-dns" || got.Description != "allow DNS" || len(got.Subject) != 1 {
+		t.Errorf("GetNetworkRuleSetPolicy returned %+v", got)
+	}
+
+	got.Description = "allow DNS and NTP"
+
+	updated, err := client.UpdateNetworkRuleSetPolicy(ctx, got)
+	if err != nil {
+		t.Fatalf("UpdateNetworkRuleSetPolicy: %v", err)
+	}
+
+	if updated.ID != created.ID || updated.Description != "allow DNS and NTP" {
+		t.Errorf("UpdateNetworkRuleSetPolicy returned %+v", updated)
+	}
+
+	policies, err := client.ListNetworkRuleSetPolicies(ctx)
+	if err != nil {
+		t.Fatalf("ListNetworkRuleSetPolicies: %v", err)
+	}
+
+	if len(policies) != 1 || policies[0].Description != "allow DNS and NTP" {
+		t.Errorf("ListNetworkRuleSetPolicies returned %d policies, want the updated policy", len(policies))
+	}
+
+	err = client.DeleteNetworkRuleSetPolicy(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("DeleteNetworkRuleSetPolicy: %v", err)
+	}
+
+	_, err = client.GetNetworkRuleSetPolicy(ctx, created.ID)
+	if statusCode(err) != 404 {
+		t.Errorf("GetNetworkRuleSetPolicy of a deleted policy returned %v, want 404", err)
+	}
+}
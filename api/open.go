@@ -0,0 +1,124 @@
+package prismasdk2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// Open returns a client for the namespace at path. The path may be relative to the client
+// namespace (child/grandchild) or absolute (/tenant/child/grandchild). An absolute path must be
+// the client namespace or below it. Each segment is resolved against the cached children of its
+// parent; the children are synced once if the segment is not found.
+func (t *Client) Open(ctx context.Context, path string) (*Client, error) {
+
+	t.logger.Debug("entering Open")
+
+	ctx = telemetry.WithOperation(ctx, "Open")
+
+	client, err := t.open(ctx, path, false)
+	if err != nil {
+		t.logger.Debug("returning Open with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning Open")
+	return client, nil
+}
+
+// OpenOrCreate is like Open but namespaces that do not exist are created as type
+// NamespaceTypeGroup, similar to mkdir -p.
+func (t *Client) OpenOrCreate(ctx context.Context, path string) (*Client, error) {
+
+	t.logger.Debug("entering OpenOrCreate")
+
+	ctx = telemetry.WithOperation(ctx, "OpenOrCreate")
+
+	client, err := t.open(ctx, path, true)
+	if err != nil {
+		t.logger.Debug("returning OpenOrCreate with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning OpenOrCreate")
+	return client, nil
+}
+
+func (t *Client) open(ctx context.Context, path string, create bool) (*Client, error) {
+
+	segments, err := t.relativeSegments(path)
+	if err != nil {
+		return nil, err
+	}
+
+	client := t
+
+	for _, segment := range segments {
+
+		if !client.HasNamespace(segment) {
+
+			err = client.SyncNamespaces(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !client.HasNamespace(segment) {
+
+			if !create {
+				return nil, &types.APIError{
+					Code:        404,
+					Description: fmt.Sprintf("Namespace %s not found", client.namespacePath+"/"+segment),
+				}
+			}
+
+			namespace := types.NewNamespace(segment).SetNamespaceType(types.NamespaceTypeGroup)
+
+			_, err = client.CreateNamespace(ctx, namespace)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		client, err = client.NewClient(ctx, segment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// relativeSegments returns the segments of path relative to the client namespace
+func (t *Client) relativeSegments(path string) ([]string, error) {
+
+	if strings.HasPrefix(path, "/") {
+
+		path = strings.TrimSuffix(path, "/")
+
+		if path != t.namespacePath && !strings.HasPrefix(path, t.namespacePath+"/") {
+			return nil, fmt.Errorf("namespace %s is not below %s", path, t.namespacePath)
+		}
+
+		path = strings.TrimPrefix(path, t.namespacePath)
+	}
+
+	var segments []string
+
+	for _, segment := range strings.Split(path, "/") {
+
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			return nil, fmt.Errorf("namespace path %s must not contain ..", path)
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
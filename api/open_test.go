@@ -0,0 +1,88 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func TestOpen(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant/account/group")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	for _, path := range []string{"account/group", "/tenant/account/group", "./account/group/"} {
+
+		opened, err := client.Open(context.Background(), path)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", path, err)
+		}
+
+		if opened.GetNamespacePath() != "/tenant/account/group" {
+			t.Errorf("Open(%q) returned a client for %s", path, opened.GetNamespacePath())
+		}
+	}
+
+	for _, path := range []string{"account/missing", "/other/account", "account/../account"} {
+		if _, err := client.Open(context.Background(), path); err == nil {
+			t.Errorf("Open(%q) returned no error", path)
+		}
+	}
+
+	// Open does not create the missing namespace
+	if n := countRequests(server, "POST", "/namespaces"); n != 0 {
+		t.Errorf("Open created %d namespaces", n)
+	}
+}
+
+func TestOpenOrCreate(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant/account")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	opened, err := client.OpenOrCreate(context.Background(), "account/group/leaf")
+	if err != nil {
+		t.Fatalf("OpenOrCreate: %v", err)
+	}
+
+	if opened.GetNamespacePath() != "/tenant/account/group/leaf" {
+		t.Errorf("OpenOrCreate returned a client for %s", opened.GetNamespacePath())
+	}
+
+	// Only the missing group and leaf are created
+	if n := countRequests(server, "POST", "/namespaces"); n != 2 {
+		t.Errorf("OpenOrCreate created %d namespaces, want 2", n)
+	}
+
+	for _, v := range server.Requests() {
+
+		if v.Method != "POST" || v.Path != "/namespaces" {
+			continue
+		}
+
+		var body struct {
+			Group string `json:"group"`
+		}
+
+		if err := json.Unmarshal(v.Body, &body); err != nil || body.Group != string(types.NamespaceTypeGroup) {
+			t.Errorf("OpenOrCreate created a namespace with %s, want the type Group", v.Body)
+		}
+	}
+
+	// The namespaces exist now so nothing more is created
+	_, err = client.OpenOrCreate(context.Background(), "/tenant/account/group/leaf")
+	if err != nil {
+		t.Fatalf("OpenOrCreate of an existing path: %v", err)
+	}
+
+	if n := countRequests(server, "POST", "/namespaces"); n != 2 {
+		t.Errorf("OpenOrCreate of an existing path created %d more namespaces", n-2)
+	}
+}
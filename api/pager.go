@@ -0,0 +1,294 @@
+package prismasdk2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// DefaultPageSize is the number of objects requested per page when Config.PageSize is not set
+const DefaultPageSize = 100
+
+// pager fetches a list one page at a time using the page and pagesize parameters of the Prisma
+// API. The total number of objects is taken from the X-Count-Total header when present. If the
+// parameters are ignored, as by a proxy that drops the query, paging stops after the first page
+// instead of fetching the same objects again.
+type pager struct {
+	client    *Client
+	operation string
+	path      string
+	query     url.Values
+	header    http.Header
+	pageSize  int
+
+	page  int
+	total int
+	first json.RawMessage
+	items []json.RawMessage
+	index int
+	done  bool
+	err   error
+}
+
+func (t *Client) newPager(operation, path string, query url.Values, header http.Header) *pager {
+
+	pageSize := t.pageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &pager{
+		client:    t,
+		operation: operation,
+		path:      path,
+		query:     query,
+		header:    header,
+		pageSize:  pageSize,
+		total:     -1,
+	}
+}
+
+// next unmarshals the next object into out. It returns false when there are no more objects or
+// an error occurred.
+func (t *pager) next(ctx context.Context, out interface{}) bool {
+
+	if t.err != nil {
+		return false
+	}
+
+	for t.index >= len(t.items) {
+
+		if t.done {
+			return false
+		}
+
+		err := t.fetch(ctx)
+		if err != nil {
+			t.err = err
+			return false
+		}
+	}
+
+	err := json.Unmarshal(t.items[t.index], out)
+	t.index++
+
+	if err != nil {
+		t.err = err
+		return false
+	}
+
+	return true
+}
+
+func (t *pager) fetch(ctx context.Context) error {
+
+	t.page++
+
+	if telemetry.OperationFromContext(ctx) == "" {
+		ctx = telemetry.WithOperation(ctx, t.operation)
+	}
+
+	query := url.Values{}
+	for k, v := range t.query {
+		query[k] = v
+	}
+	query.Set("page", strconv.Itoa(t.page))
+	query.Set("pagesize", strconv.Itoa(t.pageSize))
+
+	var items []json.RawMessage
+
+	respHeader, err := t.client.execute(ctx, &request{
+		method: "GET",
+		path:   t.path + "?" + query.Encode(),
+		header: t.header,
+		out:    &items,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if total, err := strconv.Atoi(respHeader.Get("X-Count-Total")); err == nil {
+		t.total = total
+	}
+
+	t.client.logger.Debug("fetched page",
+		zap.String("namespace", t.client.namespacePath),
+		zap.String("path", t.path),
+		zap.Int("page", t.page),
+		zap.Int("total", t.total),
+	)
+
+	// A page starting with the first object of the previous page means the page parameter was
+	// ignored
+	if t.page > 1 && len(items) > 0 && bytes.Equal(items[0], t.first) {
+		t.client.logger.Warn("paging not supported; stopping after the first page", zap.String("path", t.path))
+		items = nil
+	}
+
+	if len(items) > 0 {
+		t.first = items[0]
+	}
+
+	t.items = items
+	t.index = 0
+
+	switch {
+	case len(items) < t.pageSize:
+		t.done = true
+	case len(items) > t.pageSize:
+		// The pagesize parameter was ignored and the whole list returned
+		t.done = true
+	case t.total >= 0 && t.page*t.pageSize >= t.total:
+		t.done = true
+	}
+
+	return nil
+}
+
+// NamespaceIterator iterates over namespaces fetching one page at a time
+type NamespaceIterator struct {
+	pager *pager
+	item  *namespaceRes
+}
+
+// Next advances the iterator. It returns false when there are no more items or an error occurred.
+func (t *NamespaceIterator) Next(ctx context.Context) bool {
+	t.item = nil
+	return t.pager.next(ctx, &t.item)
+}
+
+// Item returns the current item
+func (t *NamespaceIterator) Item() *types.Namespace {
+	if t.item == nil {
+		return nil
+	}
+	return namespaceResToNamespace(t.item)
+}
+
+// Err returns the error that stopped the iteration if any
+func (t *NamespaceIterator) Err() error {
+	return t.pager.err
+}
+
+// Total returns the total number of items as reported by the API or -1 if unknown
+func (t *NamespaceIterator) Total() int {
+	return t.pager.total
+}
+
+// NetworkRuleSetPolicyIterator iterates over network rule set policies fetching one page at a time
+type NetworkRuleSetPolicyIterator struct {
+	pager *pager
+	item  *types.Networkrulesetpolicy
+}
+
+// Next advances the iterator. It returns false when there are no more items or an error occurred.
+func (t *NetworkRuleSetPolicyIterator) Next(ctx context.Context) bool {
+	t.item = nil
+	return t.pager.next(ctx, &t.item)
+}
+
+// Item returns the current item
+func (t *NetworkRuleSetPolicyIterator) Item() *types.Networkrulesetpolicy {
+	return t.item
+}
+
+// Err returns the error that stopped the iteration if any
+func (t *NetworkRuleSetPolicyIterator) Err() error {
+	return t.pager.err
+}
+
+// Total returns the total number of items as reported by the API or -1 if unknown
+func (t *NetworkRuleSetPolicyIterator) Total() int {
+	return t.pager.total
+}
+
+// ExternalNetworkIterator iterates over external networks fetching one page at a time
+type ExternalNetworkIterator struct {
+	pager *pager
+	item  *types.Externalnetwork
+}
+
+// Next advances the iterator. It returns false when there are no more items or an error occurred.
+func (t *ExternalNetworkIterator) Next(ctx context.Context) bool {
+	t.item = nil
+	return t.pager.next(ctx, &t.item)
+}
+
+// Item returns the current item
+func (t *ExternalNetworkIterator) Item() *types.Externalnetwork {
+	return t.item
+}
+
+// Err returns the error that stopped the iteration if any
+func (t *ExternalNetworkIterator) Err() error {
+	return t.pager.err
+}
+
+// Total returns the total number of items as reported by the API or -1 if unknown
+func (t *ExternalNetworkIterator) Total() int {
+	return t.pager.total
+}
+
+// APIAuthorizationPolicyIterator iterates over API authorization policies fetching one page at a
+// time
+type APIAuthorizationPolicyIterator struct {
+	pager *pager
+	item  *types.APIAuthorizationPolicy
+}
+
+// Next advances the iterator. It returns false when there are no more items or an error occurred.
+func (t *APIAuthorizationPolicyIterator) Next(ctx context.Context) bool {
+	t.item = nil
+	return t.pager.next(ctx, &t.item)
+}
+
+// Item returns the current item
+func (t *APIAuthorizationPolicyIterator) Item() *types.APIAuthorizationPolicy {
+	return t.item
+}
+
+// Err returns the error that stopped the iteration if any
+func (t *APIAuthorizationPolicyIterator) Err() error {
+	return t.pager.err
+}
+
+// Total returns the total number of items as reported by the API or -1 if unknown
+func (t *APIAuthorizationPolicyIterator) Total() int {
+	return t.pager.total
+}
+
+// AppCredentialIterator iterates over app credentials fetching one page at a time
+type AppCredentialIterator struct {
+	pager *pager
+	item  *types.AppCredential
+}
+
+// Next advances the iterator. It returns false when there are no more items or an error occurred.
+func (t *AppCredentialIterator) Next(ctx context.Context) bool {
+	t.item = nil
+	return t.pager.next(ctx, &t.item)
+}
+
+// Item returns the current item
+func (t *AppCredentialIterator) Item() *types.AppCredential {
+	return t.item
+}
+
+// Err returns the error that stopped the iteration if any
+func (t *AppCredentialIterator) Err() error {
+	return t.pager.err
+}
+
+// Total returns the total number of items as reported by the API or -1 if unknown
+func (t *AppCredentialIterator) Total() int {
+	return t.pager.total
+}
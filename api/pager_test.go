@@ -0,0 +1,206 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func newTestClient(t *testing.T, api string, server *prismatest.Server, config *prisma_api.Config) *prisma_api.Client {
+
+	t.Helper()
+
+	if config == nil {
+		config = prisma_api.NewConfig()
+	}
+
+	client, err := config.
+		SetAPI(api).
+		SetNamespace("/tenant").
+		SetTokenProvider(server.TokenProvider()).
+		Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	return client
+}
+
+func createExternalNetworks(t *testing.T, client *prisma_api.Client, n int) {
+
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		_, err := client.CreateExternalNetwork(context.Background(), &types.Externalnetwork{
+			Name:    fmt.Sprintf("network-%d", i),
+			Entries: []string{"10.0.0.0/8"},
+		})
+		if err != nil {
+			t.Fatalf("CreateExternalNetwork: %v", err)
+		}
+	}
+}
+
+func countRequests(server *prismatest.Server, method, path string) int {
+	n := 0
+	for _, v := range server.Requests() {
+		if v.Method == method && v.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func iterateExternalNetworks(t *testing.T, client *prisma_api.Client) []string {
+
+	t.Helper()
+
+	var names []string
+
+	iter := client.IterateExternalNetworks()
+	for iter.Next(context.Background()) {
+		names = append(names, iter.Item().Name)
+	}
+
+	if err := iter.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	return names
+}
+
+func TestIteratorFetchesPages(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, prisma_api.NewConfig().SetPageSize(2))
+	createExternalNetworks(t, client, 5)
+
+	iter := client.IterateExternalNetworks()
+
+	var names []string
+	for iter.Next(context.Background()) {
+		names = append(names, iter.Item().Name)
+	}
+
+	if err := iter.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	if len(names) != 5 {
+		t.Fatalf("got %d items, want 5: %v", len(names), names)
+	}
+
+	for i, v := range names {
+		if want := fmt.Sprintf("network-%d", i); v != want {
+			t.Errorf("item %d is %s, want %s", i, v, want)
+		}
+	}
+
+	if iter.Total() != 5 {
+		t.Errorf("Total is %d, want 5", iter.Total())
+	}
+
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 3 {
+		t.Errorf("got %d page requests, want 3", n)
+	}
+}
+
+func TestIteratorStopsAtTotal(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, prisma_api.NewConfig().SetPageSize(2))
+	createExternalNetworks(t, client, 4)
+
+	names := iterateExternalNetworks(t, client)
+
+	if len(names) != 4 {
+		t.Fatalf("got %d items, want 4", len(names))
+	}
+
+	// The second page is full but X-Count-Total says there is nothing left
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 2 {
+		t.Errorf("got %d page requests, want 2", n)
+	}
+}
+
+// newPagingProxy returns a proxy that drops the query and the X-Count-Total header like a proxy
+// that does not support paging
+func newPagingProxy(t *testing.T, server *prismatest.Server) *httptest.Server {
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.URL.RawQuery = ""
+	}
+
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		resp.Header.Del("X-Count-Total")
+		return nil
+	}
+
+	return httptest.NewServer(proxy)
+}
+
+func TestIteratorIgnoredPageSize(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	proxy := newPagingProxy(t, server)
+	defer proxy.Close()
+
+	client := newTestClient(t, proxy.URL, server, prisma_api.NewConfig().SetPageSize(2))
+	createExternalNetworks(t, client, 5)
+
+	names := iterateExternalNetworks(t, client)
+
+	if len(names) != 5 {
+		t.Fatalf("got %d items, want 5: %v", len(names), names)
+	}
+
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 1 {
+		t.Errorf("got %d page requests, want 1", n)
+	}
+}
+
+func TestIteratorIgnoredPage(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	proxy := newPagingProxy(t, server)
+	defer proxy.Close()
+
+	// Every response is a full page so only the repeated first item shows that paging is ignored
+	client := newTestClient(t, proxy.URL, server, prisma_api.NewConfig().SetPageSize(3))
+	createExternalNetworks(t, client, 3)
+
+	names := iterateExternalNetworks(t, client)
+
+	if len(names) != 3 {
+		t.Fatalf("got %d items, want 3: %v", len(names), names)
+	}
+
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 2 {
+		t.Errorf("got %d page requests, want 2", n)
+	}
+}
@@ -0,0 +1,189 @@
+package prismasdk2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// request is a request against the Prisma API scoped to the client namespace. If in is not nil it
+// is marshaled as the request body. If out is not nil the response body is unmarshaled into it.
+type request struct {
+	method string
+	path   string
+	header http.Header
+	in     interface{}
+	out    interface{}
+}
+
+// execute is the request pipeline shared by all API calls. It builds the request, sets the
+// common headers and the bearer token, sends it through the middleware chain with the retry
+// policy, replays it once if the token is rejected and maps unsuccessful status codes to types.APIError. Every
+// request is logged with the namespace, method, path, status code and latency and is traced and
+// measured under the operation carried by ctx. The response headers are returned.
+func (t *Client) execute(ctx context.Context, r *request) (http.Header, error) {
+
+	token, err := t.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+
+	if r.in != nil {
+		j, err := json.Marshal(r.in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBuffer(j)
+	}
+
+	ctx, done := t.instrumentation.StartRequest(ctx, r.method, r.path, t.namespacePath)
+
+	req, err := http.NewRequestWithContext(ctx, r.method, t.api+r.path, body)
+	if err != nil {
+		done(nil, err)
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-Namespace", t.namespacePath)
+	req.Header.Add("Authorization", "Bearer "+token)
+
+	for k, v := range r.header {
+		for _, e := range v {
+			req.Header.Add(k, e)
+		}
+	}
+
+	logger := t.logger.With(
+		zap.String("namespace", t.namespacePath),
+		zap.String("method", r.method),
+		zap.String("path", r.path),
+	)
+
+	start := time.Now()
+
+	resp, err := t.do(req)
+	done(resp, err)
+	if err != nil {
+		logger.Debug("request failed", zap.Duration("latency", time.Since(start)), zap.Error(err))
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		logger.Debug("request failed", zap.Duration("latency", time.Since(start)), zap.Error(err))
+		return nil, err
+	}
+
+	logger = logger.With(zap.Int("status", resp.StatusCode), zap.Duration("latency", time.Since(start)))
+
+	switch resp.StatusCode {
+	case 200, 201, 204:
+		logger.Debug("request completed")
+
+	default:
+		logger.Debug("request failed")
+		return nil, types.NewAPIErrorWithCode(resp.StatusCode, respBytes)
+	}
+
+	if r.out == nil || len(respBytes) == 0 {
+		return resp.Header, nil
+	}
+
+	return resp.Header, json.Unmarshal(respBytes, r.out)
+}
+
+// doJSON executes a request with the specified method, path, body and result
+func (t *Client) doJSON(ctx context.Context, method, path string, in, out interface{}) error {
+
+	_, err := t.execute(ctx, &request{
+		method: method,
+		path:   path,
+		in:     in,
+		out:    out,
+	})
+
+	return err
+}
+
+// do sends the request through the middleware chain applying the retry policy of the client. If
+// the API rejects the token (see tokenRejected) and the TokenProvider implements Invalidator the
+// token is invalidated and the request is replayed once with a new token.
+func (t *Client) do(req *http.Request) (*http.Response, error) {
+
+	resp, err := t.retryPolicy.Do(req, retry.SendFunc(t.handler))
+	if err != nil || !tokenRejected(req, resp) {
+		return resp, err
+	}
+
+	invalidator, ok := t.TokenProvider.(Invalidator)
+	if !ok {
+		return resp, nil
+	}
+
+	replay := req.Clone(req.Context())
+
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		replay.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+	}
+
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	t.logger.Debug("token rejected; invalidating token and replaying request",
+		zap.String("namespace", t.namespacePath),
+		zap.String("method", req.Method),
+		zap.String("path", req.URL.Path),
+	)
+
+	invalidator.Invalidate()
+
+	token, err := t.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	replay.Header.Set("Authorization", "Bearer "+token)
+
+	return t.retryPolicy.Do(replay, retry.SendFunc(t.handler))
+}
+
+// tokenRejected returns true if resp rejects the token of req. A 401 Unauthorized always does; a
+// revoked token is answered with 401. A 403 Forbidden only does if the token has expired. Otherwise
+// the token is valid and the 403 denies the permission to its identity, which a new token for the
+// same identity would not change; replaying it would only issue a token for every denied request.
+func tokenRejected(req *http.Request, resp *http.Response) bool {
+
+	switch resp.StatusCode {
+
+	case http.StatusUnauthorized:
+		return true
+
+	case http.StatusForbidden:
+		claims, err := common.ParseToken(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+		return err == nil && claims.IsExpired(0)
+	}
+
+	return false
+}
@@ -0,0 +1,347 @@
+package prismasdk2_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	token_envvars "github.com/aporeto-se/prisma-sdk-go-v2/token/aws/envvars"
+	token_cache "github.com/aporeto-se/prisma-sdk-go-v2/token/cache"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func statusCode(err error) int {
+	var apiErr *types.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
+func TestRetryAfter(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	server.AddFailure(&prismatest.Failure{
+		Method:     "GET",
+		Path:       "/externalnetworks",
+		StatusCode: 429,
+		Header:     http.Header{"Retry-After": []string{"1"}},
+	})
+
+	start := time.Now()
+
+	_, err := client.ListExternalNetworks(context.Background())
+	if err != nil {
+		t.Fatalf("ListExternalNetworks: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the Retry-After of 1s", elapsed)
+	}
+
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
+
+func TestRetryAfterExceedsMaxBackoff(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, prisma_api.NewConfig().
+		SetRetryPolicy(retry.NewPolicy().SetMaxBackoff(time.Second)))
+
+	server.AddFailure(&prismatest.Failure{
+		Method:     "GET",
+		Path:       "/externalnetworks",
+		StatusCode: 503,
+		Header:     http.Header{"Retry-After": []string{"3600"}},
+	})
+
+	_, err := client.ListExternalNetworks(context.Background())
+	if statusCode(err) != 503 {
+		t.Fatalf("ListExternalNetworks returned %v, want 503", err)
+	}
+
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+func TestReplayOn401(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	server.RevokeTokens()
+
+	err := client.SyncNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("SyncNamespaces after revoking the token: %v", err)
+	}
+
+	// The request of Build, the rejected request and the replay with a new token
+	if n := countRequests(server, "GET", "/namespaces"); n != 3 {
+		t.Errorf("got %d requests, want 3", n)
+	}
+}
+
+func TestReplayOn401WithCache(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	cache, err := token_cache.NewConfig().
+		SetDir(t.TempDir()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	tokenProvider, err := token_envvars.NewConfig().
+		SetAPI(server.URL).
+		SetAccessKeyID("AKIAEXAMPLE").
+		SetSecretAccessKey("secret").
+		SetSessionToken("session").
+		SetCache(cache).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	client, err := prisma_api.NewConfig().
+		SetAPI(server.URL).
+		SetNamespace("/tenant").
+		SetTokenProvider(tokenProvider).
+		Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	server.RevokeTokens()
+
+	// The revoked token must not be read back from the cache for the replay
+	err = client.SyncNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("SyncNamespaces after revoking the token: %v", err)
+	}
+
+	if n := countRequests(server, "POST", "/issue"); n != 2 {
+		t.Errorf("got %d requests for a token, want 2", n)
+	}
+}
+
+func TestReplayOn401Once(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	server.AddFailure(&prismatest.Failure{
+		Path:       "/externalnetworks",
+		StatusCode: 401,
+		Times:      2,
+	})
+
+	_, err := client.ListExternalNetworks(context.Background())
+	if statusCode(err) != 401 {
+		t.Fatalf("ListExternalNetworks returned %v, want 401", err)
+	}
+
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
+
+// expiringTokenProvider returns an expired token once expire is called until it is invalidated
+type expiringTokenProvider struct {
+	*prismatest.TokenProvider
+	mutex   sync.Mutex
+	expired string
+}
+
+func (t *expiringTokenProvider) expire() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	payload := fmt.Sprintf(`{"realm":"Certificate","exp":%d}`, time.Now().Add(-time.Minute).Unix())
+	t.expired = "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func (t *expiringTokenProvider) Token(ctx context.Context) (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.expired != "" {
+		return t.expired, nil
+	}
+	return t.TokenProvider.Token(ctx)
+}
+
+func (t *expiringTokenProvider) Invalidate() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.expired = ""
+	t.TokenProvider.Invalidate()
+}
+
+func TestReplayOn403WithExpiredToken(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	tokenProvider := &expiringTokenProvider{TokenProvider: server.TokenProvider()}
+
+	client, err := prisma_api.NewConfig().
+		SetAPI(server.URL).
+		SetNamespace("/tenant").
+		SetTokenProvider(tokenProvider).
+		Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	tokenProvider.expire()
+
+	server.AddFailure(&prismatest.Failure{
+		Path:       "/externalnetworks",
+		StatusCode: 403,
+		Times:      1,
+	})
+
+	_, err = client.ListExternalNetworks(context.Background())
+	if err != nil {
+		t.Fatalf("ListExternalNetworks with an expired token: %v", err)
+	}
+
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
+
+func TestNoReplayOn403WithValidToken(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, server, nil)
+
+	server.AddFailure(&prismatest.Failure{
+		Path:       "/externalnetworks",
+		StatusCode: 403,
+		Times:      1,
+	})
+
+	// The identity of a valid token lacks the permission; a new token would not change that
+	_, err := client.ListExternalNetworks(context.Background())
+	if statusCode(err) != 403 {
+		t.Fatalf("ListExternalNetworks returned %v, want 403", err)
+	}
+
+	if n := countRequests(server, "GET", "/externalnetworks"); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
+
+// staticTokenProvider does not implement Invalidator
+type staticTokenProvider struct {
+	token string
+}
+
+func (t *staticTokenProvider) Token(ctx context.Context) (string, error) {
+	return t.token, nil
+}
+
+func (t *staticTokenProvider) AccountID(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+func TestNoReplayWithoutInvalidator(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client, err := prisma_api.NewConfig().
+		SetAPI(server.URL).
+		SetNamespace("/tenant").
+		SetTokenProvider(&staticTokenProvider{token: server.Token()}).
+		Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	server.RevokeTokens()
+
+	err = client.SyncNamespaces(context.Background())
+	if statusCode(err) != 401 {
+		t.Fatalf("SyncNamespaces returned %v, want 401", err)
+	}
+
+	if n := countRequests(server, "GET", "/namespaces"); n != 2 {
+		t.Errorf("got %d requests, want 2", n)
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	var mutex sync.Mutex
+	var calls []string
+
+	// record records the name of the middleware and the header set by the middlewares before it
+	record := func(name string) prisma_api.Middleware {
+		return func(next prisma_api.Handler) prisma_api.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				mutex.Lock()
+				calls = append(calls, name+" "+req.Header.Get("X-Test"))
+				mutex.Unlock()
+				return next(req)
+			}
+		}
+	}
+
+	client := newTestClient(t, server.URL, server, prisma_api.NewConfig().
+		SetRetryPolicy(retry.NewPolicy().SetInitialBackoff(time.Millisecond)).
+		Use(prisma_api.WithHeader("X-Test", "first"), record("first"), prisma_api.WithHeader("X-Test", "second"), record("second")))
+
+	server.AddFailure(&prismatest.Failure{
+		Method:     "GET",
+		Path:       "/externalnetworks",
+		StatusCode: 503,
+	})
+
+	mutex.Lock()
+	calls = nil
+	mutex.Unlock()
+
+	_, err := client.ListExternalNetworks(context.Background())
+	if err != nil {
+		t.Fatalf("ListExternalNetworks: %v", err)
+	}
+
+	// The chain is called once per attempt, outermost middleware first
+	want := []string{"first first", "second second", "first first", "second second"}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if !equal(calls, want) {
+		t.Errorf("middlewares were called as %q, want %q", calls, want)
+	}
+}
@@ -10,3 +10,11 @@ type TokenProvider interface {
 	Token(context.Context) (string, error)
 	AccountID(ctx context.Context) (string, error)
 }
+
+// Invalidator is optionally implemented by a TokenProvider. Invalidate discards the current token
+// so that the next call to Token returns a new one. The client calls it when the API rejects a
+// token with 401 Unauthorized, or with 403 Forbidden once the token has expired, and then replays
+// the request once.
+type Invalidator interface {
+	Invalidate()
+}
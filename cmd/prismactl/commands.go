@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func nsList(ctx context.Context, g *globals, args []string) error {
+
+	client, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tID\tINCOMING\tOUTGOING\tDESCRIPTION")
+
+	for _, ns := range client.GetNamespaces() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", ns.Name, ns.ID, ns.DefaultPUIncomingTrafficAction, ns.DefaultPUOutgoingTrafficAction, ns.Description)
+	}
+
+	return w.Flush()
+}
+
+func nsCreate(ctx context.Context, g *globals, args []string) error {
+
+	flags := flag.NewFlagSet("ns create", flag.ExitOnError)
+	namespaceType := flags.String("type", string(types.NamespaceTypeGroup), "namespace type (Group or Kubernetes)")
+	description := flags.String("description", "", "namespace description")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: prismactl ns create [-type Group|Kubernetes] [-description description] name")
+	}
+
+	nsType, err := types.NamespaceTypeFromString(*namespaceType)
+	if err != nil {
+		return fmt.Errorf("unknown namespace type %s; usage: prismactl ns create [-type Group|Kubernetes] [-description description] name", *namespaceType)
+	}
+
+	client, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	ns, err := client.CreateNamespace(ctx, types.NewNamespace(flags.Arg(0)).
+		SetNamespaceType(nsType).
+		SetDescription(*description))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(ns.ID)
+	return nil
+}
+
+func nsDelete(ctx context.Context, g *globals, args []string) error {
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: prismactl ns delete name")
+	}
+
+	client, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteNamespace(ctx, args[0])
+}
+
+func nsTree(ctx context.Context, g *globals, args []string) error {
+
+	flags := flag.NewFlagSet("ns tree", flag.ExitOnError)
+	depth := flags.Int("depth", 0, "maximum depth (0 is unlimited)")
+	flags.Parse(args)
+
+	client, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	tree, err := client.NamespaceTree(ctx, *depth)
+	if err != nil {
+		return err
+	}
+
+	return tree.Walk(func(node *prisma_api.NamespaceNode) error {
+		if node.Depth == 0 {
+			fmt.Println(node.Path)
+			return nil
+		}
+		fmt.Println(strings.Repeat("  ", node.Depth) + node.Namespace.Name)
+		return nil
+	})
+}
+
+func importConfig(ctx context.Context, g *globals, args []string) error {
+
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	file := flags.String("f", "", "PrismaConfig file (YAML or JSON)")
+	flags.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("usage: prismactl import -f config.yaml")
+	}
+
+	data, err := ioutil.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	var prismaConfig *types.PrismaConfig
+
+	err = yaml.Unmarshal(data, &prismaConfig)
+	if err != nil {
+		return err
+	}
+
+	client, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	return client.ImportPrismaConfig(ctx, prismaConfig)
+}
+
+func exportConfig(ctx context.Context, g *globals, args []string) error {
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	label := flags.String("label", "", "only export objects imported with this label")
+	recursive := flags.Bool("recursive", false, "export the child namespaces as well")
+	output := flags.String("o", "", "output file (default stdout)")
+	flags.Parse(args)
+
+	client, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	prismaConfig, err := client.Export(ctx, prisma_api.NewExportConfig().
+		AddIdentities(flags.Args()...).
+		SetLabel(*label).
+		SetRecursive(*recursive))
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(prismaConfig)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return ioutil.WriteFile(*output, data, 0644)
+}
+
+func tokenShow(ctx context.Context, g *globals, args []string) error {
+
+	tokenProvider, err := g.detectTokenProvider(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, err := tokenProvider.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	claims, err := common.ParseToken(token)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "token source: %s\n", tokenProvider.Selected())
+	fmt.Fprintf(os.Stderr, "expires in: %s\n", claims.ExpiresIn().Round(time.Second))
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(claims)
+}
+
+func tokenIssue(ctx context.Context, g *globals, args []string) error {
+
+	tokenProvider, err := g.detectTokenProvider(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, err := tokenProvider.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(token)
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	token_cache "github.com/aporeto-se/prisma-sdk-go-v2/token/cache"
+	token_chain "github.com/aporeto-se/prisma-sdk-go-v2/token/chain"
+)
+
+type globals struct {
+	api           string
+	namespace     string
+	tokenCache    bool
+	tokenProvider *token_chain.Client
+}
+
+// detectTokenProvider returns a chain that selects the first token provider usable in this
+// environment
+func (t *globals) detectTokenProvider(ctx context.Context) (*token_chain.Client, error) {
+
+	if t.tokenProvider != nil {
+		return t.tokenProvider, nil
+	}
+
+	config := token_chain.NewConfig().SetAPI(t.api)
+
+	if t.tokenCache {
+
+		cache, err := token_cache.NewConfig().Build()
+		if err != nil {
+			return nil, err
+		}
+
+		config.SetCache(cache)
+	}
+
+	tokenProvider, err := config.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	t.tokenProvider = tokenProvider
+	return tokenProvider, nil
+}
+
+// client returns a client for the namespace
+func (t *globals) client(ctx context.Context) (*prisma_api.Client, error) {
+
+	if t.api == "" {
+		return nil, fmt.Errorf("flag -api or env var %s is required", APIEnv)
+	}
+
+	if t.namespace == "" {
+		return nil, fmt.Errorf("flag -namespace or env var %s is required", NamespaceEnv)
+	}
+
+	tokenProvider, err := t.detectTokenProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return prisma_api.NewConfig().
+		SetAPI(t.api).
+		SetNamespace(t.namespace).
+		SetTokenProvider(tokenProvider).
+		Build(ctx)
+}
@@ -0,0 +1,111 @@
+/*
+prismactl is a small command line tool for the Prisma API built on this SDK. It obtains a token
+the same way services using the SDK do and then operates on the namespace set with -namespace
+or the env var NAMESPACE. The API is set with -api or the env var API.
+
+The token source is detected in this order: a token in PRISMA_TOKEN, APOCTL_TOKEN or
+ENFORCERD_TOKEN, AWS credentials in AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+AWS_SESSION_TOKEN, the GCP metadata server, an Azure managed identity and the AWS metadata
+server. With -token-cache issued tokens are kept in the user cache directory and reused by the
+next runs until they expire.
+
+	prismactl ns list
+	prismactl ns create [-type Group] name
+	prismactl ns delete name
+	prismactl ns tree [-depth n]
+	prismactl import -f config.yaml
+	prismactl export [-label label] [-recursive] [-o file] [identity...]
+	prismactl token show
+	prismactl token issue
+
+Build a static binary with CGO_ENABLED=0 go build ./cmd/prismactl
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const (
+
+	// APIEnv enviroment variable
+	APIEnv = "API"
+
+	// NamespaceEnv enviroment variable
+	NamespaceEnv = "NAMESPACE"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, g *globals, args []string) error
+}
+
+var commands = []*command{
+	{"ns list", "list the child namespaces", nsList},
+	{"ns create", "create a child namespace", nsCreate},
+	{"ns delete", "delete a child namespace", nsDelete},
+	{"ns tree", "print the namespace tree", nsTree},
+	{"import", "import a PrismaConfig file", importConfig},
+	{"export", "export the namespace as a PrismaConfig", exportConfig},
+	{"token show", "print the claims of the token", tokenShow},
+	{"token issue", "print the token", tokenIssue},
+}
+
+func main() {
+
+	g := &globals{}
+
+	flags := flag.NewFlagSet("prismactl", flag.ExitOnError)
+	flags.StringVar(&g.api, "api", os.Getenv(APIEnv), "Prisma API URL (env "+APIEnv+")")
+	flags.StringVar(&g.namespace, "namespace", os.Getenv(NamespaceEnv), "namespace (env "+NamespaceEnv+")")
+	flags.BoolVar(&g.tokenCache, "token-cache", false, "reuse issued tokens across runs from the user cache directory")
+	flags.Usage = func() { usage(flags) }
+	flags.Parse(os.Args[1:])
+
+	cmd, args := findCommand(flags.Args())
+	if cmd == nil {
+		usage(flags)
+		os.Exit(2)
+	}
+
+	err := cmd.run(context.Background(), g, args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func findCommand(args []string) (*command, []string) {
+
+	for _, cmd := range commands {
+
+		if len(args) >= 1 && args[0] == cmd.name {
+			return cmd, args[1:]
+		}
+
+		if len(args) >= 2 && args[0]+" "+args[1] == cmd.name {
+			return cmd, args[2:]
+		}
+	}
+
+	return nil, nil
+}
+
+func usage(flags *flag.FlagSet) {
+
+	fmt.Fprintln(os.Stderr, "usage: prismactl [flags] command [args]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "flags:")
+	flags.PrintDefaults()
+}
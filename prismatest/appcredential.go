@@ -0,0 +1,104 @@
+package prismatest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// serveAppCredentials serves app credentials. The credential file is returned on create and update
+// only; an update regenerates the certificate and revokes the previous one.
+func (t *Server) serveAppCredentials(w http.ResponseWriter, r *http.Request, namespace string, segments []string) {
+
+	if len(segments) == 0 {
+
+		switch r.Method {
+
+		case "GET":
+			writeJSON(w, http.StatusOK, page(w, r, t.store.listObjects("appcredentials", namespace, recursive(r))))
+
+		case "POST":
+			var in object
+			if !readJSON(w, r, &in) {
+				return
+			}
+
+			name, _ := in["name"].(string)
+			if name == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Validation Error", "attribute name is required")
+				return
+			}
+
+			if roles, _ := in["roles"].([]interface{}); len(roles) == 0 {
+				writeError(w, http.StatusUnprocessableEntity, "Validation Error", "attribute roles is required")
+				return
+			}
+
+			delete(in, "credentials")
+
+			o := t.store.createObject("appcredentials", namespace, in)
+			t.writeAppCredential(w, o)
+
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", r.Method)
+		}
+
+		return
+	}
+
+	o := t.store.objectByID("appcredentials", namespace, segments[0])
+	if o == nil {
+		writeError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("object %s not found", segments[0]))
+		return
+	}
+
+	switch r.Method {
+
+	case "GET":
+		writeJSON(w, http.StatusOK, o)
+
+	case "PUT":
+		var in object
+		if !readJSON(w, r, &in) {
+			return
+		}
+		update(o, in, "credentials", "certificateSerialNumber")
+		t.revokeAppCredential(o)
+		t.writeAppCredential(w, o)
+
+	case "DELETE":
+		t.revokeAppCredential(o)
+		t.store.deleteObjects("appcredentials", func(v object) bool {
+			return v["ID"] == o["ID"]
+		})
+		writeJSON(w, http.StatusOK, o)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", r.Method)
+	}
+}
+
+// writeAppCredential generates a new credential for the app credential and writes both
+func (t *Server) writeAppCredential(w http.ResponseWriter, o object) {
+
+	credential, serialNumber, err := t.newCredential(o["ID"].(string), o["namespace"].(string), o["name"].(string))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Internal Server Error", err.Error())
+		return
+	}
+
+	o["certificateSerialNumber"] = serialNumber
+
+	result := object{}
+	for k, v := range o {
+		result[k] = v
+	}
+	result["credentials"] = credential
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (t *Server) revokeAppCredential(o object) {
+	if serialNumber, ok := o["certificateSerialNumber"].(string); ok {
+		delete(t.certificates, serialNumber)
+	}
+}
@@ -0,0 +1,82 @@
+package prismatest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// Credential returns a new app credential for the Server in namespace. The certificate is
+// self-signed; the Server accepts the client certificates it generated until they are rotated or
+// deleted.
+func (t *Server) Credential(namespace, name string) (*prisma_types.Credential, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	credential, _, err := t.newCredential(fmt.Sprintf("%024x", time.Now().UnixNano()), namespace, name)
+	return credential, err
+}
+
+// newCredential returns a new credential and the serial number of its certificate
+func (t *Server) newCredential(id, namespace, name string) (*prisma_types.Credential, string, error) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(now.UnixNano()),
+		Subject: pkix.Name{
+			CommonName:   fmt.Sprintf("app:credential:%s:%s", id, name),
+			Organization: []string{namespace},
+		},
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    now.Add(24 * time.Hour),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	credential := &prisma_types.Credential{
+		ID:             id,
+		Name:           name,
+		Namespace:      namespace,
+		APIURL:         t.URL,
+		Certificate:    encodePEM("CERTIFICATE", certificate),
+		CertificateKey: encodePEM("EC PRIVATE KEY", keyBytes),
+	}
+
+	if serverCertificate := t.Server.Certificate(); serverCertificate != nil {
+		credential.CertificateAuthority = encodePEM("CERTIFICATE", serverCertificate.Raw)
+	}
+
+	serialNumber := template.SerialNumber.String()
+	t.certificates[serialNumber] = true
+
+	return credential, serialNumber, nil
+}
+
+// encodePEM returns the base64 encoded PEM block as found in credential files
+func encodePEM(blockType string, data []byte) string {
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}))
+}
@@ -0,0 +1,218 @@
+package prismatest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func (t *Server) serveNamespaces(w http.ResponseWriter, r *http.Request, namespace string, segments []string) {
+
+	if len(segments) == 0 {
+
+		switch r.Method {
+
+		case "GET":
+			writeJSON(w, http.StatusOK, page(w, r, t.store.listNamespaces(namespace, recursive(r))))
+
+		case "POST":
+			var in object
+			if !readJSON(w, r, &in) {
+				return
+			}
+
+			name, _ := in["name"].(string)
+			if name == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Validation Error", "attribute name is required")
+				return
+			}
+
+			if t.store.hasNamespace(namespace + "/" + name) {
+				writeError(w, http.StatusConflict, "Conflict", fmt.Sprintf("namespace %s already exists", name))
+				return
+			}
+
+			for _, k := range readOnlyAttributes {
+				delete(in, k)
+			}
+
+			writeJSON(w, http.StatusOK, t.store.createNamespace(namespace, in))
+
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", r.Method)
+		}
+
+		return
+	}
+
+	ns := t.store.namespaceByID(segments[0])
+	if ns == nil || !inScope(ns["namespace"].(string), namespace, true) {
+		writeError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("namespace %s not found", segments[0]))
+		return
+	}
+
+	switch r.Method {
+
+	case "GET":
+		writeJSON(w, http.StatusOK, ns)
+
+	case "PUT":
+		var in object
+		if !readJSON(w, r, &in) {
+			return
+		}
+		update(ns, in, "name")
+		writeJSON(w, http.StatusOK, ns)
+
+	case "DELETE":
+		t.store.deleteNamespace(ns["name"].(string))
+		writeJSON(w, http.StatusOK, ns)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", r.Method)
+	}
+}
+
+func (t *Server) serveObjects(w http.ResponseWriter, r *http.Request, namespace, identity string, segments []string) {
+
+	if len(segments) == 0 {
+
+		switch r.Method {
+
+		case "GET":
+			writeJSON(w, http.StatusOK, page(w, r, t.store.listObjects(identity, namespace, recursive(r))))
+
+		case "POST":
+			var in object
+			if !readJSON(w, r, &in) {
+				return
+			}
+
+			if name, _ := in["name"].(string); name == "" {
+				writeError(w, http.StatusUnprocessableEntity, "Validation Error", "attribute name is required")
+				return
+			}
+
+			writeJSON(w, http.StatusOK, t.store.createObject(identity, namespace, in))
+
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", r.Method)
+		}
+
+		return
+	}
+
+	o := t.store.objectByID(identity, namespace, segments[0])
+	if o == nil {
+		writeError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("object %s not found", segments[0]))
+		return
+	}
+
+	switch r.Method {
+
+	case "GET":
+		writeJSON(w, http.StatusOK, o)
+
+	case "PUT":
+		var in object
+		if !readJSON(w, r, &in) {
+			return
+		}
+		update(o, in)
+		writeJSON(w, http.StatusOK, o)
+
+	case "DELETE":
+		t.store.deleteObjects(identity, func(v object) bool {
+			return v["ID"] == o["ID"]
+		})
+		writeJSON(w, http.StatusOK, o)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", r.Method)
+	}
+}
+
+type importReq struct {
+	Data struct {
+		Label string              `json:"label"`
+		Data  map[string][]object `json:"data"`
+	} `json:"data"`
+}
+
+// serveImport replaces the objects previously imported with the same label in the namespace
+func (t *Server) serveImport(w http.ResponseWriter, r *http.Request, namespace string) {
+
+	var req importReq
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	label := req.Data.Label
+	if label == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Error", "attribute label is required")
+		return
+	}
+
+	for identity := range req.Data.Data {
+		if !isObjectIdentity(identity) {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Error", fmt.Sprintf("identity %s is not supported", identity))
+			return
+		}
+	}
+
+	for _, identity := range identities {
+
+		t.store.deleteObjects(identity, func(v object) bool {
+			return v["namespace"] == namespace && v["importLabel"] == label
+		})
+
+		for _, in := range req.Data.Data[identity] {
+			o := t.store.createObject(identity, namespace, in)
+			j, _ := json.Marshal(in)
+			o["importLabel"] = label
+			o["importHash"] = fmt.Sprintf("%x", sha256.Sum256(j))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type exportReq struct {
+	Identities []string `json:"identities"`
+	Label      string   `json:"label"`
+}
+
+type exportRes struct {
+	APIVersion int                 `json:"APIVersion"`
+	Label      string              `json:"label,omitempty"`
+	Identities []string            `json:"identities"`
+	Data       map[string][]object `json:"data"`
+}
+
+func (t *Server) serveExport(w http.ResponseWriter, r *http.Request, namespace string) {
+
+	var req exportReq
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	res := &exportRes{
+		Label:      req.Label,
+		Identities: req.Identities,
+		Data:       make(map[string][]object),
+	}
+
+	for _, v := range req.Identities {
+
+		identity, ok := identities[v]
+		if !ok {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Error", fmt.Sprintf("identity %s is not supported", v))
+			return
+		}
+
+		res.Data[identity] = t.store.listObjects(identity, namespace, recursive(r))
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
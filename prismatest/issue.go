@@ -0,0 +1,115 @@
+package prismatest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+type issueReq struct {
+	Realm string `json:"realm"`
+	common.IssueOptions
+	subject string
+}
+
+func (t *Server) serveIssue(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed", "use POST")
+		return
+	}
+
+	var req issueReq
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Realm == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Error", "attribute realm is required")
+		return
+	}
+
+	if req.Validity == "" {
+		req.Validity = common.DefaultValidity.String()
+	}
+
+	if _, err := time.ParseDuration(req.Validity); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Error", fmt.Sprintf("invalid validity %s", req.Validity))
+		return
+	}
+
+	if req.Quota < 0 {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Error", "attribute quota must not be negative")
+		return
+	}
+
+	if req.Realm == "Certificate" && r.TLS != nil {
+		if len(r.TLS.PeerCertificates) == 0 {
+			writeError(w, http.StatusUnauthorized, "Unauthorized", "a client certificate is required for realm Certificate")
+			return
+		}
+		if !t.certificates[r.TLS.PeerCertificates[0].SerialNumber.String()] {
+			writeError(w, http.StatusUnauthorized, "Unauthorized", "unknown or revoked client certificate")
+			return
+		}
+		req.subject = r.TLS.PeerCertificates[0].Subject.CommonName
+	}
+
+	writeJSON(w, http.StatusOK, t.issue(&req))
+}
+
+// issue returns a new token. The token is an unsigned JWT so that its claims can be decoded. The
+// restrictions are recorded in the claims but not enforced.
+func (t *Server) issue(req *issueReq) *common.PrismaToken {
+
+	realm := req.Realm
+	duration, _ := time.ParseDuration(req.Validity)
+	now := time.Now()
+
+	token := &common.PrismaToken{
+		Realm:                 realm,
+		Validity:              req.Validity,
+		Quota:                 req.Quota,
+		Audience:              req.Audience,
+		RestrictedNamespace:   req.RestrictedNamespace,
+		RestrictedNetworks:    req.RestrictedNetworks,
+		RestrictedPermissions: req.RestrictedPermissions,
+	}
+
+	token.Claims.Realm = realm
+	token.Claims.Iss = t.URL
+	token.Claims.Iat = now.Unix()
+	token.Claims.Exp = now.Add(duration).Unix()
+	token.Claims.Sub = fmt.Sprintf("prismatest-%d", now.UnixNano())
+	token.Claims.Data.Subject = req.subject
+	token.Claims.Data.Realm = realm
+
+	// The cloud account is in the claim of the realm like in the tokens of the API
+	switch realm {
+	case "AWSSecurityToken":
+		token.Claims.Data.Organization = t.accountID
+	case "GCPIdentityToken":
+		token.Claims.Data.Projectnumber = t.accountID
+	case "AzureIdentityToken":
+		token.Claims.Data.Subscriptionid = t.accountID
+	}
+
+	token.Claims.Restrictions.Namespace = req.RestrictedNamespace
+	token.Claims.Restrictions.Networks = req.RestrictedNetworks
+	token.Claims.Restrictions.Permissions = req.RestrictedPermissions
+
+	header, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	claims, _ := json.Marshal(token.Claims)
+
+	token.Token = base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(claims) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte("prismatest"))
+
+	t.tokens[token.Token] = true
+
+	return token
+}
@@ -0,0 +1,355 @@
+package prismatest
+
+/*
+
+This implements an in-process fake of the Prisma API for tests. It serves the endpoints used by
+the api package and the token providers against an in-memory store:
+
+/issue (with mutual TLS for the Certificate realm when started with NewTLSServer)
+/namespaces and /namespaces/:id (scoped by the X-Namespace header)
+/import and /export
+/appcredentials and /appcredentials/:id (a new credential file is returned on create and update)
+/apiauthorizationpolicies, /externalnetworks and /networkrulesetpolicies (and /:id)
+
+Errors are returned in the same shape as the Prisma API so that types.NewAPIError can parse them.
+Failures such as 429 or 401 can be scripted with AddFailure.
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client, err := prisma_api.NewConfig().
+		SetAPI(server.URL).
+		SetNamespace("/tenant").
+		SetTokenProvider(server.TokenProvider()).
+		Build(ctx)
+
+*/
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+// Failure is a scripted failure. Requests matching Method and Path are answered with StatusCode
+// instead of being served. An empty Method or Path matches any request; Path matches by prefix.
+type Failure struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Times      int
+	Header     http.Header
+}
+
+// Request is a request received by the Server
+type Request struct {
+	Method    string
+	Path      string
+	Namespace string
+	Body      []byte
+}
+
+// Server is an in-process fake of the Prisma API
+type Server struct {
+	*httptest.Server
+	mutex        sync.Mutex
+	store        *store
+	tokens       map[string]bool
+	certificates map[string]bool
+	failures     []*Failure
+	requests     []*Request
+	accountID    string
+}
+
+// NewServer returns a new started Server with the specified namespaces. Missing parents of the
+// namespaces are created as well. The caller must call Close when done.
+func NewServer(namespaces ...string) *Server {
+
+	t := newServer(namespaces)
+	t.Server.Start()
+
+	return t
+}
+
+// NewTLSServer returns a new started Server like NewServer that is served over TLS. Client
+// certificates are requested and /issue requires one for the Certificate realm. Credentials for
+// the Server are returned by Credential.
+func NewTLSServer(namespaces ...string) *Server {
+
+	t := newServer(namespaces)
+	t.Server.TLS = &tls.Config{
+		ClientAuth: tls.RequestClientCert,
+	}
+	t.Server.StartTLS()
+
+	return t
+}
+
+func newServer(namespaces []string) *Server {
+
+	t := &Server{
+		store:        newStore(),
+		tokens:       make(map[string]bool),
+		certificates: make(map[string]bool),
+		accountID:    "123456789012",
+	}
+
+	for _, v := range namespaces {
+		t.store.mkdirAll(v)
+	}
+
+	t.Server = httptest.NewUnstartedServer(http.HandlerFunc(t.serveHTTP))
+
+	return t
+}
+
+// SetAccountID sets the cloud account ID returned in the claims of issued tokens and returns self
+func (t *Server) SetAccountID(v string) *Server {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.accountID = v
+	return t
+}
+
+// AddNamespace creates the namespace at path and its missing parents
+func (t *Server) AddNamespace(path string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.store.mkdirAll(path)
+}
+
+// AddFailure scripts a failure. Failures are matched in the order they are added.
+func (t *Server) AddFailure(failure *Failure) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if failure.Times <= 0 {
+		failure.Times = 1
+	}
+	t.failures = append(t.failures, failure)
+}
+
+// Requests returns the requests received so far
+func (t *Server) Requests() []*Request {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var result []*Request
+	return append(result, t.requests...)
+}
+
+// Count returns the number of requests received so far for path
+func (t *Server) Count(path string) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	n := 0
+	for _, v := range t.requests {
+		if v.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// Token issues a new valid token
+func (t *Server) Token() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.issue(&issueReq{
+		Realm: "Certificate",
+		IssueOptions: common.IssueOptions{
+			Validity: common.DefaultValidity.String(),
+		},
+	}).Token
+}
+
+// RevokeTokens revokes all the tokens issued so far. Requests with a revoked token are answered
+// with 401.
+func (t *Server) RevokeTokens() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.tokens = make(map[string]bool)
+}
+
+// TokenProvider returns a TokenProvider that issues tokens from the Server. It implements
+// Invalidate so that a revoked token is replaced.
+func (t *Server) TokenProvider() *TokenProvider {
+	return &TokenProvider{
+		server: t,
+	}
+}
+
+// TokenProvider is a TokenProvider backed by a Server
+type TokenProvider struct {
+	server *Server
+	mutex  sync.Mutex
+	token  string
+}
+
+// Token returns token string or error
+func (t *TokenProvider) Token(ctx context.Context) (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.token == "" {
+		t.token = t.server.Token()
+	}
+	return t.token, nil
+}
+
+// AccountID returns Cloud Account ID or error
+func (t *TokenProvider) AccountID(ctx context.Context) (string, error) {
+	t.server.mutex.Lock()
+	defer t.server.mutex.Unlock()
+	return t.server.accountID, nil
+}
+
+// Claims returns the claims of the current token
+func (t *TokenProvider) Claims(ctx context.Context) (*common.Claims, error) {
+	token, err := t.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return common.ParseToken(token)
+}
+
+// Invalidate discards the current token so that the next call to Token issues a new one
+func (t *TokenProvider) Invalidate() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.token = ""
+}
+
+func (t *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.requests = append(t.requests, &Request{
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Namespace: r.Header.Get("X-Namespace"),
+		Body:      body,
+	})
+
+	if t.scriptedFailure(w, r) {
+		return
+	}
+
+	if r.URL.Path == "/issue" {
+		t.serveIssue(w, r)
+		return
+	}
+
+	if !t.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "Unauthorized", "invalid or revoked token")
+		return
+	}
+
+	namespace := r.Header.Get("X-Namespace")
+	if !t.store.hasNamespace(namespace) {
+		writeError(w, http.StatusForbidden, "Forbidden", fmt.Sprintf("namespace %s does not exist", namespace))
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+
+	case segments[0] == "import" && len(segments) == 1 && r.Method == "POST":
+		t.serveImport(w, r, namespace)
+
+	case segments[0] == "export" && len(segments) == 1 && r.Method == "POST":
+		t.serveExport(w, r, namespace)
+
+	case segments[0] == "namespaces":
+		t.serveNamespaces(w, r, namespace, segments[1:])
+
+	case segments[0] == "appcredentials":
+		t.serveAppCredentials(w, r, namespace, segments[1:])
+
+	case isObjectIdentity(segments[0]):
+		t.serveObjects(w, r, namespace, segments[0], segments[1:])
+
+	default:
+		writeError(w, http.StatusNotFound, "Not Found", fmt.Sprintf("unknown path %s", r.URL.Path))
+	}
+}
+
+func (t *Server) scriptedFailure(w http.ResponseWriter, r *http.Request) bool {
+
+	for i, v := range t.failures {
+
+		if v.Method != "" && v.Method != r.Method {
+			continue
+		}
+
+		if v.Path != "" && !strings.HasPrefix(r.URL.Path, v.Path) {
+			continue
+		}
+
+		v.Times--
+		if v.Times <= 0 {
+			t.failures = append(t.failures[:i], t.failures[i+1:]...)
+		}
+
+		for k, values := range v.Header {
+			for _, e := range values {
+				w.Header().Add(k, e)
+			}
+		}
+
+		writeError(w, v.StatusCode, http.StatusText(v.StatusCode), "scripted failure")
+		return true
+	}
+
+	return false
+}
+
+func (t *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return t.tokens[token]
+}
+
+type apiError struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+	Subject     string `json:"subject"`
+	Title       string `json:"title"`
+}
+
+// writeError writes an error in the shape returned by the Prisma API
+func writeError(w http.ResponseWriter, code int, title, description string) {
+	writeJSON(w, code, []*apiError{
+		{
+			Code:        code,
+			Description: description,
+			Subject:     "prismatest",
+			Title:       title,
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return false
+	}
+	return true
+}
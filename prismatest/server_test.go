@@ -0,0 +1,302 @@
+package prismatest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_appcred "github.com/aporeto-se/prisma-sdk-go-v2/token/appcred"
+)
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func send(t *testing.T, client *http.Client, method, url, namespace, token string, in interface{}) *response {
+
+	t.Helper()
+
+	var body bytes.Buffer
+	if in != nil {
+		json.NewEncoder(&body).Encode(in)
+	}
+
+	req, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if namespace != "" {
+		req.Header.Set("X-Namespace", namespace)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	var out bytes.Buffer
+	out.ReadFrom(resp.Body)
+
+	return &response{
+		status: resp.StatusCode,
+		header: resp.Header,
+		body:   out.Bytes(),
+	}
+}
+
+func names(t *testing.T, resp *response) []string {
+
+	t.Helper()
+
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(resp.body, &objects); err != nil {
+		t.Fatalf("invalid list %s: %v", resp.body, err)
+	}
+
+	var result []string
+	for _, v := range objects {
+		result = append(result, v["name"].(string))
+	}
+
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAuthorization(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	if resp := send(t, server.Client(), "GET", server.URL+"/namespaces", "/tenant", "", nil); resp.status != 401 {
+		t.Errorf("request without token returned %d, want 401", resp.status)
+	}
+
+	token := server.Token()
+
+	if resp := send(t, server.Client(), "GET", server.URL+"/namespaces", "/tenant", token, nil); resp.status != 200 {
+		t.Errorf("request with token returned %d, want 200", resp.status)
+	}
+
+	if resp := send(t, server.Client(), "GET", server.URL+"/namespaces", "/other", token, nil); resp.status != 403 {
+		t.Errorf("request in unknown namespace returned %d, want 403", resp.status)
+	}
+
+	server.RevokeTokens()
+
+	if resp := send(t, server.Client(), "GET", server.URL+"/namespaces", "/tenant", token, nil); resp.status != 401 {
+		t.Errorf("request with revoked token returned %d, want 401", resp.status)
+	}
+}
+
+func TestNamespaceScope(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant/a", "/tenant/b")
+	defer server.Close()
+
+	token := server.Token()
+
+	for _, namespace := range []string{"/tenant/a", "/tenant/b"} {
+		resp := send(t, server.Client(), "POST", server.URL+"/externalnetworks", namespace, token, map[string]interface{}{
+			"name": "in " + namespace,
+		})
+		if resp.status != 200 {
+			t.Fatalf("create returned %d: %s", resp.status, resp.body)
+		}
+	}
+
+	tests := []struct {
+		path      string
+		namespace string
+		want      []string
+	}{
+		{"/externalnetworks", "/tenant/a", []string{"in /tenant/a"}},
+		{"/externalnetworks", "/tenant", nil},
+		{"/externalnetworks?recursive=true", "/tenant", []string{"in /tenant/a", "in /tenant/b"}},
+		{"/namespaces", "/tenant", []string{"/tenant/a", "/tenant/b"}},
+	}
+
+	for _, test := range tests {
+		resp := send(t, server.Client(), "GET", server.URL+test.path, test.namespace, token, nil)
+		if got := names(t, resp); !equal(got, test.want) {
+			t.Errorf("GET %s in %s returned %v, want %v", test.path, test.namespace, got, test.want)
+		}
+	}
+}
+
+func TestPaging(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant/a", "/tenant/b", "/tenant/c")
+	defer server.Close()
+
+	token := server.Token()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"/tenant/a", "/tenant/b", "/tenant/c"}},
+		{"?page=1&pagesize=2", []string{"/tenant/a", "/tenant/b"}},
+		{"?page=2&pagesize=2", []string{"/tenant/c"}},
+		{"?page=3&pagesize=2", nil},
+	}
+
+	for _, test := range tests {
+
+		resp := send(t, server.Client(), "GET", server.URL+"/namespaces"+test.query, "/tenant", token, nil)
+
+		if got := names(t, resp); !equal(got, test.want) {
+			t.Errorf("GET /namespaces%s returned %v, want %v", test.query, got, test.want)
+		}
+
+		if total := resp.header.Get("X-Count-Total"); total != "3" {
+			t.Errorf("GET /namespaces%s returned X-Count-Total %s, want 3", test.query, total)
+		}
+	}
+}
+
+func TestImportReplacesLabel(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	token := server.Token()
+
+	importNetworks := func(label string, names ...string) {
+
+		var networks []map[string]interface{}
+		for _, v := range names {
+			networks = append(networks, map[string]interface{}{"name": v})
+		}
+
+		resp := send(t, server.Client(), "POST", server.URL+"/import", "/tenant", token, map[string]interface{}{
+			"data": map[string]interface{}{
+				"label": label,
+				"data":  map[string]interface{}{"externalnetworks": networks},
+			},
+		})
+		if resp.status != 204 {
+			t.Fatalf("import returned %d: %s", resp.status, resp.body)
+		}
+	}
+
+	importNetworks("one", "a", "b")
+	importNetworks("two", "c")
+	importNetworks("one", "d")
+
+	resp := send(t, server.Client(), "GET", server.URL+"/externalnetworks", "/tenant", token, nil)
+	if got, want := names(t, resp), []string{"c", "d"}; !equal(got, want) {
+		t.Errorf("after import got %v, want %v", got, want)
+	}
+}
+
+func TestAddFailure(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	token := server.Token()
+
+	server.AddFailure(&prismatest.Failure{
+		Method:     "GET",
+		Path:       "/namespaces",
+		StatusCode: 429,
+		Times:      2,
+		Header:     http.Header{"Retry-After": []string{"1"}},
+	})
+
+	if resp := send(t, server.Client(), "POST", server.URL+"/namespaces", "/tenant", token, map[string]interface{}{"name": "a"}); resp.status != 200 {
+		t.Errorf("unmatched request returned %d, want 200", resp.status)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := send(t, server.Client(), "GET", server.URL+"/namespaces", "/tenant", token, nil)
+		if resp.status != 429 || resp.header.Get("Retry-After") != "1" {
+			t.Errorf("scripted failure %d returned %d with Retry-After %q", i, resp.status, resp.header.Get("Retry-After"))
+		}
+	}
+
+	if resp := send(t, server.Client(), "GET", server.URL+"/namespaces", "/tenant", token, nil); resp.status != 200 {
+		t.Errorf("request after the scripted failures returned %d, want 200", resp.status)
+	}
+
+	if n := len(server.Requests()); n != 4 {
+		t.Errorf("got %d requests, want 4", n)
+	}
+
+	if n := server.Count("/namespaces"); n != 4 {
+		t.Errorf("got %d requests for /namespaces, want 4", n)
+	}
+}
+
+func TestIssueCertificate(t *testing.T) {
+
+	server := prismatest.NewTLSServer("/tenant")
+	defer server.Close()
+
+	issue := func(client *http.Client) int {
+		return send(t, client, "POST", server.URL+"/issue", "", "", map[string]interface{}{"realm": "Certificate"}).status
+	}
+
+	if status := issue(server.Client()); status != 401 {
+		t.Errorf("issue without client certificate returned %d, want 401", status)
+	}
+
+	credential, err := server.Credential("/tenant", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tlsConfig, err := token_appcred.TLSConfig(credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	if status := issue(client); status != 200 {
+		t.Errorf("issue with client certificate returned %d, want 200", status)
+	}
+
+	// A certificate not generated by the server is rejected
+	other := prismatest.NewTLSServer("/tenant")
+	defer other.Close()
+
+	credential, err = other.Credential("/tenant", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential.CertificateAuthority = ""
+
+	tlsConfig, err = token_appcred.TLSConfig(credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig.RootCAs = server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	if status := issue(client); status != 401 {
+		t.Errorf("issue with unknown client certificate returned %d, want 401", status)
+	}
+}
@@ -0,0 +1,260 @@
+package prismatest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// object is a Prisma object as decoded from JSON
+type object map[string]interface{}
+
+// identities maps the singular identity names used by import and export to the plural names used
+// in paths and in the data of import and export
+var identities = map[string]string{
+	"apiauthorizationpolicy": "apiauthorizationpolicies",
+	"externalnetwork":        "externalnetworks",
+	"networkrulesetpolicy":   "networkrulesetpolicies",
+}
+
+func isObjectIdentity(plural string) bool {
+	for _, v := range identities {
+		if v == plural {
+			return true
+		}
+	}
+	return false
+}
+
+// readOnlyAttributes are ignored when an object is created or updated
+var readOnlyAttributes = []string{"ID", "namespace", "createTime", "updateTime", "importHash", "importLabel"}
+
+type store struct {
+	nextID     int
+	namespaces map[string]object
+	objects    map[string][]object
+}
+
+func newStore() *store {
+	return &store{
+		namespaces: make(map[string]object),
+		objects:    make(map[string][]object),
+	}
+}
+
+func (t *store) newID() string {
+	t.nextID++
+	return fmt.Sprintf("%024x", t.nextID)
+}
+
+func (t *store) hasNamespace(path string) bool {
+	_, ok := t.namespaces[path]
+	return ok
+}
+
+// mkdirAll creates the namespace at path and its missing parents
+func (t *store) mkdirAll(path string) {
+
+	path = "/" + strings.Trim(path, "/")
+
+	if path == "/" || t.hasNamespace(path) {
+		return
+	}
+
+	parent := path[:strings.LastIndex(path, "/")]
+	t.mkdirAll(parent)
+
+	if parent == "" {
+		parent = "/"
+	}
+
+	t.createNamespace(parent, object{
+		"name": path[strings.LastIndex(path, "/")+1:],
+		"type": "Group",
+	})
+}
+
+func (t *store) createNamespace(parent string, in object) object {
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	ns := object{}
+	for k, v := range in {
+		ns[k] = v
+	}
+
+	ns["ID"] = t.newID()
+	ns["name"] = strings.TrimSuffix(parent, "/") + "/" + in["name"].(string)
+	ns["namespace"] = parent
+	ns["createTime"] = now
+	ns["updateTime"] = now
+
+	t.namespaces[ns["name"].(string)] = ns
+
+	return ns
+}
+
+func (t *store) namespaceByID(id string) object {
+	for _, v := range t.namespaces {
+		if v["ID"] == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// deleteNamespace deletes the namespace, its descendants and their objects
+func (t *store) deleteNamespace(path string) {
+
+	for name := range t.namespaces {
+		if name == path || strings.HasPrefix(name, path+"/") {
+			delete(t.namespaces, name)
+		}
+	}
+
+	for identity, objects := range t.objects {
+		var kept []object
+		for _, v := range objects {
+			if !inScope(v["namespace"].(string), path, true) {
+				kept = append(kept, v)
+			}
+		}
+		t.objects[identity] = kept
+	}
+}
+
+// listNamespaces returns the children of namespace or all descendants if recursive
+func (t *store) listNamespaces(namespace string, recursive bool) []object {
+
+	result := []object{}
+
+	for _, v := range t.namespaces {
+		parent := v["namespace"].(string)
+		if parent == namespace || (recursive && strings.HasPrefix(parent, namespace+"/")) {
+			result = append(result, v)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i]["name"].(string) < result[j]["name"].(string)
+	})
+
+	return result
+}
+
+func (t *store) createObject(identity, namespace string, in object) object {
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	o := object{}
+	for k, v := range in {
+		o[k] = v
+	}
+
+	for _, k := range readOnlyAttributes {
+		delete(o, k)
+	}
+
+	o["ID"] = t.newID()
+	o["namespace"] = namespace
+	o["createTime"] = now
+	o["updateTime"] = now
+
+	t.objects[identity] = append(t.objects[identity], o)
+
+	return o
+}
+
+func (t *store) listObjects(identity, namespace string, recursive bool) []object {
+
+	result := []object{}
+
+	for _, v := range t.objects[identity] {
+		if inScope(v["namespace"].(string), namespace, recursive) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func (t *store) objectByID(identity, namespace, id string) object {
+	for _, v := range t.objects[identity] {
+		if v["ID"] == id && inScope(v["namespace"].(string), namespace, true) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (t *store) deleteObjects(identity string, match func(object) bool) {
+	var kept []object
+	for _, v := range t.objects[identity] {
+		if !match(v) {
+			kept = append(kept, v)
+		}
+	}
+	t.objects[identity] = kept
+}
+
+// update sets the attributes of in on o except the read-only attributes and the ignored
+// attributes
+func update(o, in object, ignored ...string) {
+
+	skip := make(map[string]bool)
+
+	for _, k := range append(ignored, readOnlyAttributes...) {
+		skip[k] = true
+	}
+
+	for k, v := range in {
+		if !skip[k] {
+			o[k] = v
+		}
+	}
+
+	o["updateTime"] = time.Now().UTC().Format(time.RFC3339)
+}
+
+func inScope(objectNamespace, namespace string, recursive bool) bool {
+	if objectNamespace == namespace {
+		return true
+	}
+	return recursive && strings.HasPrefix(objectNamespace, namespace+"/")
+}
+
+// page returns the page of objects requested with the page and pagesize parameters and sets the
+// X-Count-Total header
+func page(w http.ResponseWriter, r *http.Request, objects []object) []object {
+
+	w.Header().Set("X-Count-Total", strconv.Itoa(len(objects)))
+
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("pagesize"))
+	if err != nil || pageSize <= 0 {
+		return objects
+	}
+
+	pageNumber, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || pageNumber <= 0 {
+		pageNumber = 1
+	}
+
+	start := (pageNumber - 1) * pageSize
+	if start >= len(objects) {
+		return []object{}
+	}
+
+	end := start + pageSize
+	if end > len(objects) {
+		end = len(objects)
+	}
+
+	return objects[start:end]
+}
+
+func recursive(r *http.Request) bool {
+	return r.URL.Query().Get("recursive") == "true"
+}
@@ -0,0 +1,332 @@
+package retry
+
+/*
+
+This implements retries with exponential backoff and jitter for the Prisma API. The Prisma API
+documents the following status codes as temporary
+
+423 Locked: The API is locked for write operations during maintenance.
+429 Too Many Requests: You have been rate limited.
+502 Bad Gateway, 503 Service Unavailable, 504 Gateway Timeout: Temporary communication failure.
+
+A Retry-After header sent with a temporary status code is used as the delay instead of the
+backoff. If it asks for a longer delay than MaxBackoff the response is returned without retrying
+so that the caller is not blocked for longer than the policy allows.
+
+By default only idempotent requests are retried. A POST request can be marked as idempotent by
+setting the Idempotency-Key header; like net/http the header may be set to nil so that it is not
+sent on the wire.
+
+*/
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxAttempts is the default maximum number of attempts including the first one
+	DefaultMaxAttempts = 4
+
+	// DefaultInitialBackoff is the default delay before the first retry
+	DefaultInitialBackoff = 500 * time.Millisecond
+
+	// DefaultMaxBackoff is the default maximum delay between attempts
+	DefaultMaxBackoff = 30 * time.Second
+
+	// DefaultMultiplier is the default factor the delay is multiplied by after each attempt
+	DefaultMultiplier = 2.0
+
+	// DefaultJitter is the default fraction of the delay that is randomized
+	DefaultJitter = 0.2
+)
+
+// DefaultStatusCodes are the status codes that are retried by default
+var DefaultStatusCodes = []int{423, 429, 502, 503, 504}
+
+// SendFunc sends a single request
+type SendFunc func(*http.Request) (*http.Response, error)
+
+// Policy is a retry policy. A nil Policy makes a single attempt.
+type Policy struct {
+	MaxAttempts        int
+	InitialBackoff     time.Duration
+	MaxBackoff         time.Duration
+	Multiplier         float64
+	Jitter             float64
+	StatusCodes        []int
+	RetryNonIdempotent bool
+	Logger             *zap.Logger
+}
+
+// NewPolicy returns new Policy with defaults
+func NewPolicy() *Policy {
+	return &Policy{
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+		Multiplier:     DefaultMultiplier,
+		Jitter:         DefaultJitter,
+		StatusCodes:    DefaultStatusCodes,
+	}
+}
+
+// NoRetry returns a Policy that makes a single attempt
+func NoRetry() *Policy {
+	return &Policy{
+		MaxAttempts: 1,
+	}
+}
+
+// SetMaxAttempts sets the maximum number of attempts including the first one and returns self
+func (t *Policy) SetMaxAttempts(v int) *Policy {
+	t.MaxAttempts = v
+	return t
+}
+
+// SetInitialBackoff sets attribute and returns self
+func (t *Policy) SetInitialBackoff(v time.Duration) *Policy {
+	t.InitialBackoff = v
+	return t
+}
+
+// SetMaxBackoff sets attribute and returns self
+func (t *Policy) SetMaxBackoff(v time.Duration) *Policy {
+	t.MaxBackoff = v
+	return t
+}
+
+// SetMultiplier sets attribute and returns self
+func (t *Policy) SetMultiplier(v float64) *Policy {
+	t.Multiplier = v
+	return t
+}
+
+// SetJitter sets the fraction (0 to 1) of the delay that is randomized and returns self
+func (t *Policy) SetJitter(v float64) *Policy {
+	t.Jitter = v
+	return t
+}
+
+// SetStatusCodes sets the status codes that are retried and returns self
+func (t *Policy) SetStatusCodes(v ...int) *Policy {
+	t.StatusCodes = v
+	return t
+}
+
+// SetRetryNonIdempotent sets attribute and returns self. If true all requests are retried
+// regardless of the method.
+func (t *Policy) SetRetryNonIdempotent(v bool) *Policy {
+	t.RetryNonIdempotent = v
+	return t
+}
+
+// SetLogger sets entity and returns self. If not set nothing is logged.
+func (t *Policy) SetLogger(v *zap.Logger) *Policy {
+	t.Logger = v
+	return t
+}
+
+// WithLogger returns the policy if it has a logger and otherwise a copy of the policy that logs to
+// logger. The clients use this so that a policy shared between clients logs to their logger.
+func (t *Policy) WithLogger(logger *zap.Logger) *Policy {
+
+	if t == nil || t.Logger != nil {
+		return t
+	}
+
+	policy := *t
+	policy.Logger = logger
+	return &policy
+}
+
+// Do sends req with send and retries it as specified by the policy. The response of the last
+// attempt is returned. The request body is replayed with req.GetBody; requests with a body that
+// can not be replayed are not retried.
+func (t *Policy) Do(req *http.Request, send SendFunc) (*http.Response, error) {
+
+	if t == nil || t.MaxAttempts <= 1 || !t.retryable(req) {
+		return send(req)
+	}
+
+	ctx := req.Context()
+	logger := t.logger()
+
+	for attempt := 1; ; attempt++ {
+
+		r := req
+
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := send(r)
+
+		if attempt >= t.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var delay time.Duration
+
+		switch {
+
+		case err != nil:
+			delay = t.backoff(attempt)
+			logger.Debug("retrying request after error",
+				zap.String("method", req.Method),
+				zap.String("path", req.URL.Path),
+				zap.Int("attempt", attempt),
+				zap.Duration("delay", delay),
+				zap.Error(err))
+
+		case t.retryableStatus(resp.StatusCode):
+			delay = t.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if t.MaxBackoff > 0 && retryAfter > t.MaxBackoff {
+					logger.Debug("not retrying request; Retry-After exceeds the maximum backoff",
+						zap.String("method", req.Method),
+						zap.String("path", req.URL.Path),
+						zap.Int("status", resp.StatusCode),
+						zap.Int("attempt", attempt),
+						zap.Duration("retryAfter", retryAfter))
+					return resp, nil
+				}
+				delay = retryAfter
+			}
+			logger.Debug("retrying request after temporary status",
+				zap.String("method", req.Method),
+				zap.String("path", req.URL.Path),
+				zap.Int("status", resp.StatusCode),
+				zap.Int("attempt", attempt),
+				zap.Duration("delay", delay))
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+
+		default:
+			return resp, nil
+		}
+
+		err = sleep(ctx, delay)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Policy) logger() *zap.Logger {
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+	return t.Logger
+}
+
+// Retryable returns true if a response with statusCode would be retried by the policy
+func (t *Policy) Retryable(statusCode int) bool {
+	return t != nil && t.retryableStatus(statusCode)
+}
+
+func (t *Policy) retryable(req *http.Request) bool {
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	return t.RetryNonIdempotent || isIdempotent(req)
+}
+
+func (t *Policy) retryableStatus(statusCode int) bool {
+	for _, v := range t.StatusCodes {
+		if v == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Policy) backoff(attempt int) time.Duration {
+
+	multiplier := t.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(t.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+
+	if t.MaxBackoff > 0 && delay > float64(t.MaxBackoff) {
+		delay = float64(t.MaxBackoff)
+	}
+
+	if t.Jitter > 0 {
+		jitter := math.Min(t.Jitter, 1)
+		delay = delay * (1 - jitter*rand.Float64())
+	}
+
+	return time.Duration(delay)
+}
+
+func isIdempotent(req *http.Request) bool {
+
+	switch req.Method {
+	case "", "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+
+	// Same convention as net/http; the header may be nil so that it is not sent
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+
+	if _, ok := req.Header["X-Idempotency-Key"]; ok {
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter parses the Retry-After header which is either seconds or an HTTP date
+func parseRetryAfter(v string) (time.Duration, bool) {
+
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(v); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,148 @@
+package retry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newServer returns a server that answers the first failures requests with statusCode and the
+// Retry-After header and the following requests with 200
+func newServer(failures int32, statusCode int, retryAfter string) (*httptest.Server, *int32) {
+
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statusCode)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	return server, &attempts
+}
+
+func do(t *testing.T, policy *Policy, url string) *http.Response {
+
+	t.Helper()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := policy.Do(req, http.DefaultClient.Do)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	return resp
+}
+
+func TestDoRetriesTemporaryStatus(t *testing.T) {
+
+	server, attempts := newServer(2, http.StatusServiceUnavailable, "")
+	defer server.Close()
+
+	resp := do(t, NewPolicy().SetInitialBackoff(time.Millisecond), server.URL)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status is %d, want 200", resp.StatusCode)
+	}
+
+	if n := atomic.LoadInt32(attempts); n != 3 {
+		t.Errorf("got %d attempts, want 3", n)
+	}
+}
+
+func TestDoStopsAtMaxAttempts(t *testing.T) {
+
+	server, attempts := newServer(10, http.StatusTooManyRequests, "")
+	defer server.Close()
+
+	resp := do(t, NewPolicy().SetInitialBackoff(time.Millisecond).SetMaxAttempts(3), server.URL)
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status is %d, want 429", resp.StatusCode)
+	}
+
+	if n := atomic.LoadInt32(attempts); n != 3 {
+		t.Errorf("got %d attempts, want 3", n)
+	}
+}
+
+func TestDoHonorsRetryAfter(t *testing.T) {
+
+	server, attempts := newServer(1, http.StatusTooManyRequests, "1")
+	defer server.Close()
+
+	start := time.Now()
+
+	resp := do(t, NewPolicy().SetInitialBackoff(time.Millisecond), server.URL)
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status is %d, want 200", resp.StatusCode)
+	}
+
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("got %d attempts, want 2", n)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least 1s", elapsed)
+	}
+}
+
+func TestDoRetryAfterExceedsMaxBackoff(t *testing.T) {
+
+	server, attempts := newServer(1, http.StatusTooManyRequests, "3600")
+	defer server.Close()
+
+	start := time.Now()
+
+	resp := do(t, NewPolicy(), server.URL)
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status is %d, want 429", resp.StatusCode)
+	}
+
+	if resp.Header.Get("Retry-After") != "3600" {
+		t.Errorf("Retry-After is %q, want 3600", resp.Header.Get("Retry-After"))
+	}
+
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("got %d attempts, want 1", n)
+	}
+
+	if elapsed := time.Since(start); elapsed > DefaultMaxBackoff {
+		t.Errorf("returned after %s", elapsed)
+	}
+}
+
+func TestDoDoesNotRetryNonIdempotent(t *testing.T) {
+
+	server, attempts := newServer(1, http.StatusServiceUnavailable, "")
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := NewPolicy().SetInitialBackoff(time.Millisecond).Do(req, http.DefaultClient.Do)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("got %d attempts, want 1", n)
+	}
+}
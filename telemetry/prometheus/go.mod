@@ -0,0 +1,24 @@
+module github.com/aporeto-se/prisma-sdk-go-v2/telemetry/prometheus
+
+go 1.17
+
+require (
+	github.com/aporeto-se/prisma-sdk-go-v2 v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.12.2
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
+	github.com/prometheus/client_model v0.2.0 // indirect
+	github.com/prometheus/common v0.32.1 // indirect
+	github.com/prometheus/procfs v0.7.3 // indirect
+	go.opentelemetry.io/otel v1.7.0 // indirect
+	go.opentelemetry.io/otel/trace v1.7.0 // indirect
+	golang.org/x/sys v0.0.0-20220114195835-da31bd327af9 // indirect
+	google.golang.org/protobuf v1.27.1 // indirect
+)
+
+replace github.com/aporeto-se/prisma-sdk-go-v2 => ../..
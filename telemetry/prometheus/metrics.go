@@ -0,0 +1,97 @@
+package prometheus
+
+/*
+
+This implements telemetry.Metrics with Prometheus collectors. The following metrics are
+registered
+
+prisma_sdk_requests_total: counter by operation, method, namespace_depth, status_code and outcome
+prisma_sdk_request_duration_seconds: histogram by operation, method, namespace_depth and outcome
+prisma_sdk_token_issues_total: counter by provider, realm, status_code and outcome
+prisma_sdk_token_issue_duration_seconds: histogram by provider, realm and outcome
+
+The package is a module of its own so that the SDK does not depend on the Prometheus client
+unless this package is used.
+
+	go get github.com/aporeto-se/prisma-sdk-go-v2/telemetry/prometheus
+
+*/
+
+import (
+	"strconv"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+)
+
+const namespace = "prisma_sdk"
+
+// Metrics implements telemetry.Metrics
+type Metrics struct {
+	requests       *prom.CounterVec
+	requestTime    *prom.HistogramVec
+	tokenIssues    *prom.CounterVec
+	tokenIssueTime *prom.HistogramVec
+}
+
+// NewMetrics returns new Metrics registered with registerer. If registerer is nil
+// prometheus.DefaultRegisterer is used.
+func NewMetrics(registerer prom.Registerer) (*Metrics, error) {
+
+	if registerer == nil {
+		registerer = prom.DefaultRegisterer
+	}
+
+	t := &Metrics{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of requests made to the Prisma API.",
+		}, []string{"operation", "method", "namespace_depth", "status_code", "outcome"}),
+
+		requestTime: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of requests made to the Prisma API including retries.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"operation", "method", "namespace_depth", "outcome"}),
+
+		tokenIssues: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "token_issues_total",
+			Help:      "Number of tokens requested from the Prisma API.",
+		}, []string{"provider", "realm", "status_code", "outcome"}),
+
+		tokenIssueTime: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "token_issue_duration_seconds",
+			Help:      "Latency of token requests made to the Prisma API including retries.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"provider", "realm", "outcome"}),
+	}
+
+	for _, c := range []prom.Collector{t.requests, t.requestTime, t.tokenIssues, t.tokenIssueTime} {
+		err := registerer.Register(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// ObserveRequest implements telemetry.Metrics
+func (t *Metrics) ObserveRequest(r *telemetry.Request) {
+
+	depth := strconv.Itoa(r.NamespaceDepth)
+
+	t.requests.WithLabelValues(r.Operation, r.Method, depth, strconv.Itoa(r.StatusCode), string(r.Outcome)).Inc()
+	t.requestTime.WithLabelValues(r.Operation, r.Method, depth, string(r.Outcome)).Observe(r.Duration.Seconds())
+}
+
+// ObserveTokenIssue implements telemetry.Metrics
+func (t *Metrics) ObserveTokenIssue(r *telemetry.TokenIssue) {
+	t.tokenIssues.WithLabelValues(r.Provider, r.Realm, strconv.Itoa(r.StatusCode), string(r.Outcome)).Inc()
+	t.tokenIssueTime.WithLabelValues(r.Provider, r.Realm, string(r.Outcome)).Observe(r.Duration.Seconds())
+}
@@ -0,0 +1,158 @@
+package prometheus_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	telemetry_prometheus "github.com/aporeto-se/prisma-sdk-go-v2/telemetry/prometheus"
+)
+
+func TestObserveRequest(t *testing.T) {
+
+	registry := prom.NewPedanticRegistry()
+
+	metrics, err := telemetry_prometheus.NewMetrics(registry)
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+
+	metrics.ObserveRequest(&telemetry.Request{
+		Operation:      "CreateNamespace",
+		Method:         "POST",
+		NamespaceDepth: 2,
+		StatusCode:     200,
+		Outcome:        telemetry.OutcomeSuccess,
+		Duration:       30 * time.Millisecond,
+	})
+
+	metrics.ObserveRequest(&telemetry.Request{
+		Operation:      "CreateNamespace",
+		Method:         "POST",
+		NamespaceDepth: 2,
+		StatusCode:     200,
+		Outcome:        telemetry.OutcomeSuccess,
+		Duration:       2 * time.Second,
+	})
+
+	metrics.ObserveRequest(&telemetry.Request{
+		Operation: "GetNamespace",
+		Method:    "GET",
+		Outcome:   telemetry.OutcomeError,
+	})
+
+	expected := `
+# HELP prisma_sdk_requests_total Number of requests made to the Prisma API.
+# TYPE prisma_sdk_requests_total counter
+prisma_sdk_requests_total{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",status_code="0"} 1
+prisma_sdk_requests_total{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",status_code="200"} 2
+`
+
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected), "prisma_sdk_requests_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Both observations of CreateNamespace are in one histogram; one is within the 50ms bucket
+	histogram := `
+# HELP prisma_sdk_request_duration_seconds Latency of requests made to the Prisma API including retries.
+# TYPE prisma_sdk_request_duration_seconds histogram
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="0.005"} 0
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="0.01"} 0
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="0.025"} 0
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="0.05"} 1
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="0.1"} 1
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="0.25"} 1
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="0.5"} 1
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="1"} 1
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="2.5"} 2
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="5"} 2
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="10"} 2
+prisma_sdk_request_duration_seconds_bucket{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success",le="+Inf"} 2
+prisma_sdk_request_duration_seconds_sum{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success"} 2.03
+prisma_sdk_request_duration_seconds_count{method="POST",namespace_depth="2",operation="CreateNamespace",outcome="success"} 2
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="0.005"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="0.01"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="0.025"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="0.05"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="0.1"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="0.25"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="0.5"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="1"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="2.5"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="5"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="10"} 1
+prisma_sdk_request_duration_seconds_bucket{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error",le="+Inf"} 1
+prisma_sdk_request_duration_seconds_sum{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error"} 0
+prisma_sdk_request_duration_seconds_count{method="GET",namespace_depth="0",operation="GetNamespace",outcome="error"} 1
+`
+
+	err = testutil.GatherAndCompare(registry, strings.NewReader(histogram), "prisma_sdk_request_duration_seconds")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestObserveTokenIssue(t *testing.T) {
+
+	registry := prom.NewPedanticRegistry()
+
+	metrics, err := telemetry_prometheus.NewMetrics(registry)
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+
+	metrics.ObserveTokenIssue(&telemetry.TokenIssue{
+		Provider:   "gcp",
+		Realm:      "GCPIdentityToken",
+		StatusCode: 200,
+		Outcome:    telemetry.OutcomeSuccess,
+		Duration:   time.Second,
+	})
+
+	metrics.ObserveTokenIssue(&telemetry.TokenIssue{
+		Provider:   "gcp",
+		Realm:      "GCPIdentityToken",
+		StatusCode: 403,
+		Outcome:    telemetry.OutcomeError,
+		Duration:   time.Second,
+	})
+
+	expected := `
+# HELP prisma_sdk_token_issues_total Number of tokens requested from the Prisma API.
+# TYPE prisma_sdk_token_issues_total counter
+prisma_sdk_token_issues_total{outcome="error",provider="gcp",realm="GCPIdentityToken",status_code="403"} 1
+prisma_sdk_token_issues_total{outcome="success",provider="gcp",realm="GCPIdentityToken",status_code="200"} 1
+`
+
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected), "prisma_sdk_token_issues_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// The duration is labeled with the outcome but not the status code
+	n, err := testutil.GatherAndCount(registry, "prisma_sdk_token_issue_duration_seconds")
+	if err != nil || n != 2 {
+		t.Errorf("got %d token issue duration series, %v, want 2", n, err)
+	}
+}
+
+func TestNewMetricsRegistersOnce(t *testing.T) {
+
+	registry := prom.NewRegistry()
+
+	_, err := telemetry_prometheus.NewMetrics(registry)
+	if err != nil {
+		t.Fatalf("NewMetrics: %v", err)
+	}
+
+	// The collectors of a second Metrics clash with the first
+	_, err = telemetry_prometheus.NewMetrics(registry)
+	if err == nil {
+		t.Errorf("NewMetrics with the same registerer returned no error")
+	}
+}
@@ -0,0 +1,213 @@
+package telemetry
+
+/*
+
+This implements the optional instrumentation of the api client and the token providers. Every
+call to the Prisma API and every /issue exchange is wrapped in an OpenTelemetry span and reported
+to a Metrics implementation. Both default to no-ops.
+
+Requests are labeled with the operation that made them (for example CreateNamespace), the depth
+of the namespace and the outcome. The operation is carried in the context; use WithOperation to
+label requests made by your own code.
+
+*/
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName is the name of the tracer used by the SDK
+const InstrumentationName = "github.com/aporeto-se/prisma-sdk-go-v2"
+
+// Outcome is the outcome of a request
+type Outcome string
+
+const (
+	// OutcomeSuccess the request succeeded
+	OutcomeSuccess Outcome = "success"
+
+	// OutcomeError the request failed or the API returned an unsuccessful status code
+	OutcomeError Outcome = "error"
+)
+
+// Request is a completed call to the Prisma API
+type Request struct {
+	Operation      string
+	Method         string
+	NamespaceDepth int
+	StatusCode     int
+	Outcome        Outcome
+	Duration       time.Duration
+}
+
+// TokenIssue is a completed /issue exchange
+type TokenIssue struct {
+	Provider   string
+	Realm      string
+	StatusCode int
+	Outcome    Outcome
+	Duration   time.Duration
+}
+
+// Metrics records the requests made by the SDK. The StatusCode is 0 when no response was
+// received. Implementations must be safe for concurrent use.
+type Metrics interface {
+	ObserveRequest(*Request)
+	ObserveTokenIssue(*TokenIssue)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveRequest(*Request)       {}
+func (nopMetrics) ObserveTokenIssue(*TokenIssue) {}
+
+// NopMetrics returns Metrics that discards everything
+func NopMetrics() Metrics {
+	return nopMetrics{}
+}
+
+type operationKey struct{}
+
+// WithOperation returns a copy of ctx carrying the operation name used to label requests
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// OperationFromContext returns the operation name carried by ctx or an empty string
+func OperationFromContext(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}
+
+// NamespaceDepth returns the depth of namespace; the depth of /tenant is 1
+func NamespaceDepth(namespace string) int {
+	namespace = strings.Trim(namespace, "/")
+	if namespace == "" {
+		return 0
+	}
+	return strings.Count(namespace, "/") + 1
+}
+
+// DoneFunc completes the span and records the metrics of a request. resp may be nil.
+type DoneFunc func(resp *http.Response, err error)
+
+// Instrumentation is the tracer and metrics used by a client
+type Instrumentation struct {
+	tracer  trace.Tracer
+	metrics Metrics
+}
+
+// New returns new Instrumentation. A nil tracerProvider or metrics is replaced by a no-op.
+func New(tracerProvider trace.TracerProvider, metrics Metrics) *Instrumentation {
+
+	if tracerProvider == nil {
+		tracerProvider = trace.NewNoopTracerProvider()
+	}
+
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
+
+	return &Instrumentation{
+		tracer:  tracerProvider.Tracer(InstrumentationName),
+		metrics: metrics,
+	}
+}
+
+// StartRequest starts the span of a call to the Prisma API. The returned context must be used
+// for the request and the DoneFunc must be called with its result.
+func (t *Instrumentation) StartRequest(ctx context.Context, method, path, namespace string) (context.Context, DoneFunc) {
+
+	operation := OperationFromContext(ctx)
+	depth := NamespaceDepth(namespace)
+
+	name := operation
+	if name == "" {
+		name = method + " " + path
+	}
+
+	ctx, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("prisma.operation", operation),
+			attribute.String("prisma.namespace", namespace),
+			attribute.Int("prisma.namespace_depth", depth),
+			attribute.String("http.method", method),
+			attribute.String("http.target", path),
+		),
+	)
+
+	start := time.Now()
+
+	return ctx, func(resp *http.Response, err error) {
+
+		statusCode, outcome := result(span, resp, err)
+
+		t.metrics.ObserveRequest(&Request{
+			Operation:      operation,
+			Method:         method,
+			NamespaceDepth: depth,
+			StatusCode:     statusCode,
+			Outcome:        outcome,
+			Duration:       time.Since(start),
+		})
+	}
+}
+
+// StartTokenIssue starts the span of an /issue exchange. The returned context must be used for
+// the request and the DoneFunc must be called with its result.
+func (t *Instrumentation) StartTokenIssue(ctx context.Context, provider, realm string) (context.Context, DoneFunc) {
+
+	ctx, span := t.tracer.Start(ctx, "issue",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("prisma.token_provider", provider),
+			attribute.String("prisma.realm", realm),
+			attribute.String("http.method", "POST"),
+			attribute.String("http.target", "/issue"),
+		),
+	)
+
+	start := time.Now()
+
+	return ctx, func(resp *http.Response, err error) {
+
+		statusCode, outcome := result(span, resp, err)
+
+		t.metrics.ObserveTokenIssue(&TokenIssue{
+			Provider:   provider,
+			Realm:      realm,
+			StatusCode: statusCode,
+			Outcome:    outcome,
+			Duration:   time.Since(start),
+		})
+	}
+}
+
+// result sets the status of span from the response and ends it
+func result(span trace.Span, resp *http.Response, err error) (int, Outcome) {
+
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return 0, OutcomeError
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		return resp.StatusCode, OutcomeError
+	}
+
+	return resp.StatusCode, OutcomeSuccess
+}
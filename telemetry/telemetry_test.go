@@ -0,0 +1,192 @@
+package telemetry_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+)
+
+// metrics records the observations
+type metrics struct {
+	mutex       sync.Mutex
+	requests    []*telemetry.Request
+	tokenIssues []*telemetry.TokenIssue
+}
+
+func (t *metrics) ObserveRequest(r *telemetry.Request) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.requests = append(t.requests, r)
+}
+
+func (t *metrics) ObserveTokenIssue(r *telemetry.TokenIssue) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.tokenIssues = append(t.tokenIssues, r)
+}
+
+func newInstrumentation() (*telemetry.Instrumentation, *tracetest.SpanRecorder, *metrics) {
+
+	recorder := tracetest.NewSpanRecorder()
+	metrics := &metrics{}
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	return telemetry.New(tracerProvider, metrics), recorder, metrics
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	result := make(map[attribute.Key]attribute.Value)
+	for _, v := range span.Attributes() {
+		result[v.Key] = v.Value
+	}
+	return result
+}
+
+func TestStartRequest(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		resp       *http.Response
+		err        error
+		statusCode int
+		code       codes.Code
+		outcome    telemetry.Outcome
+	}{
+		{"2xx", &http.Response{StatusCode: 200}, nil, 200, codes.Unset, telemetry.OutcomeSuccess},
+		{"4xx", &http.Response{StatusCode: 404}, nil, 404, codes.Error, telemetry.OutcomeError},
+		{"transport error", nil, fmt.Errorf("connection refused"), 0, codes.Error, telemetry.OutcomeError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			instrumentation, recorder, metrics := newInstrumentation()
+
+			ctx := telemetry.WithOperation(context.Background(), "CreateNamespace")
+
+			_, done := instrumentation.StartRequest(ctx, "POST", "/namespaces", "/tenant/a")
+			done(test.resp, test.err)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("got %d ended spans, want 1", len(spans))
+			}
+
+			span := spans[0]
+
+			if span.Name() != "CreateNamespace" {
+				t.Errorf("span name is %q, want CreateNamespace", span.Name())
+			}
+
+			attrs := attributes(span)
+
+			if attrs["prisma.operation"].AsString() != "CreateNamespace" ||
+				attrs["prisma.namespace"].AsString() != "/tenant/a" ||
+				attrs["prisma.namespace_depth"].AsInt64() != 2 ||
+				attrs["http.method"].AsString() != "POST" ||
+				attrs["http.target"].AsString() != "/namespaces" {
+				t.Errorf("unexpected attributes %v", span.Attributes())
+			}
+
+			if statusCode, ok := attrs["http.status_code"]; ok != (test.resp != nil) || int(statusCode.AsInt64()) != test.statusCode {
+				t.Errorf("http.status_code is %v, want %d", statusCode.Emit(), test.statusCode)
+			}
+
+			if span.Status().Code != test.code {
+				t.Errorf("span status is %v, want %v", span.Status().Code, test.code)
+			}
+
+			if test.err != nil && (len(span.Events()) != 1 || span.Events()[0].Name != "exception") {
+				t.Errorf("error was not recorded on the span: %v", span.Events())
+			}
+
+			if len(metrics.requests) != 1 {
+				t.Fatalf("got %d observed requests, want 1", len(metrics.requests))
+			}
+
+			r := metrics.requests[0]
+
+			if r.Operation != "CreateNamespace" || r.Method != "POST" || r.NamespaceDepth != 2 ||
+				r.StatusCode != test.statusCode || r.Outcome != test.outcome {
+				t.Errorf("unexpected observed request %+v", r)
+			}
+		})
+	}
+}
+
+func TestStartRequestWithoutOperation(t *testing.T) {
+
+	instrumentation, recorder, _ := newInstrumentation()
+
+	_, done := instrumentation.StartRequest(context.Background(), "GET", "/namespaces", "/tenant")
+	done(&http.Response{StatusCode: 200}, nil)
+
+	if name := recorder.Ended()[0].Name(); name != "GET /namespaces" {
+		t.Errorf("span name is %q, want the method and path", name)
+	}
+}
+
+func TestStartTokenIssue(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		resp       *http.Response
+		err        error
+		statusCode int
+		code       codes.Code
+		outcome    telemetry.Outcome
+	}{
+		{"2xx", &http.Response{StatusCode: 200}, nil, 200, codes.Unset, telemetry.OutcomeSuccess},
+		{"4xx", &http.Response{StatusCode: 403}, nil, 403, codes.Error, telemetry.OutcomeError},
+		{"transport error", nil, fmt.Errorf("connection refused"), 0, codes.Error, telemetry.OutcomeError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			instrumentation, recorder, metrics := newInstrumentation()
+
+			_, done := instrumentation.StartTokenIssue(context.Background(), "gcp", "GCPIdentityToken")
+			done(test.resp, test.err)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("got %d ended spans, want 1", len(spans))
+			}
+
+			span := spans[0]
+			attrs := attributes(span)
+
+			if span.Name() != "issue" ||
+				attrs["prisma.token_provider"].AsString() != "gcp" ||
+				attrs["prisma.realm"].AsString() != "GCPIdentityToken" ||
+				attrs["http.target"].AsString() != "/issue" {
+				t.Errorf("unexpected span %q with attributes %v", span.Name(), span.Attributes())
+			}
+
+			if span.Status().Code != test.code {
+				t.Errorf("span status is %v, want %v", span.Status().Code, test.code)
+			}
+
+			if len(metrics.tokenIssues) != 1 {
+				t.Fatalf("got %d observed token issues, want 1", len(metrics.tokenIssues))
+			}
+
+			r := metrics.tokenIssues[0]
+
+			if r.Provider != "gcp" || r.Realm != "GCPIdentityToken" || r.StatusCode != test.statusCode || r.Outcome != test.outcome {
+				t.Errorf("unexpected observed token issue %+v", r)
+			}
+		})
+	}
+}
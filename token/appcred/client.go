@@ -0,0 +1,205 @@
+package token
+
+/*
+
+This implements the TokenProvider Interface and provides Prisma tokens using a Prisma app
+credential. The certificate of the credential authenticates to /issue with mutual TLS in the
+Certificate realm, so this works anywhere the API can be reached, including outside of a cloud.
+A new token is issued when the current one expires.
+
+The HTTP client returned by HTTPClient carries the same certificate and can be passed to the api
+package.
+
+	tokenProvider, err := token_appcred.NewConfig().SetCredentialFile("appcred.json").Build()
+
+	client, err := prisma_api.NewConfig().
+		SetAPI(tokenProvider.API()).
+		SetNamespace(tokenProvider.Namespace()).
+		SetHTTPClient(tokenProvider.HTTPClient()).
+		SetTokenProvider(tokenProvider).
+		Build(ctx)
+
+type TokenProvider interface {
+	Token(context.Context) (string, error)
+	AccountID(ctx context.Context) (string, error)
+}
+
+*/
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+// Client the token client. It is safe for concurrent use.
+type Client struct {
+	api        string
+	namespace  string
+	httpClient *http.Client
+	issuer     *common.Issuer
+	source     *common.Source
+	logger     *zap.Logger
+}
+
+// NewClient returns new Client
+func NewClient(config *Config) (*Client, error) {
+
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
+
+	credential, err := config.GetCredential()
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	api := config.API
+	if api == "" {
+		api = credential.APIURL
+	}
+
+	var errors *multierror.Error
+
+	if api == "" {
+		errors = multierror.Append(errors, fmt.Errorf("attribute API is required"))
+	}
+
+	if config.Validity < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Validity must not be negative"))
+	}
+
+	if config.Quota < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Quota must not be negative"))
+	}
+
+	tlsConfig, err := TLSConfig(credential)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	err = errors.ErrorOrNil()
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	httpClient := newHTTPClient(tlsConfig)
+
+	client := &Client{
+		api:        api,
+		namespace:  credential.Namespace,
+		httpClient: httpClient,
+		issuer: &common.Issuer{
+			API:             api,
+			Provider:        "appcred",
+			HTTPClient:      httpClient,
+			RetryPolicy:     config.GetRetryPolicy(),
+			IssueOptions:    config.GetIssueOptions(),
+			Instrumentation: telemetry.New(config.TracerProvider, config.Metrics),
+			Logger:          logger,
+		},
+		logger: logger,
+	}
+
+	cacheKey := common.CacheKey(api, "Certificate", credential.Certificate, config.GetIssueOptions())
+	client.source = common.NewSource(config.Cache, common.StaticKey(cacheKey), client.issueToken, logger)
+
+	logger.Debug("returning NewClient")
+	return client, nil
+}
+
+// API returns the API the token is issued by
+func (t *Client) API() string {
+	return t.api
+}
+
+// Namespace returns the namespace of the credential
+func (t *Client) Namespace() string {
+	return t.namespace
+}
+
+// HTTPClient returns the HTTP client authenticating with the certificate of the credential
+func (t *Client) HTTPClient() *http.Client {
+	return t.httpClient
+}
+
+// Token returns token string or error
+func (t *Client) Token(ctx context.Context) (string, error) {
+
+	t.logger.Debug("entering Token")
+
+	token, err := t.source.Token(ctx)
+	if err != nil {
+		t.logger.Debug("returning Token with error(s)")
+		return "", err
+	}
+
+	t.logger.Debug("returning Token")
+	return token.Token, nil
+}
+
+// Invalidate discards the current token so that the next call to Token fetches a new one. The
+// token is also evicted from the cache if one is set.
+func (t *Client) Invalidate() {
+	t.source.Invalidate()
+}
+
+// Refresh returns a new token even if the current one is still valid. The current token is not
+// evicted from the cache; use Invalidate if it was rejected.
+func (t *Client) Refresh(ctx context.Context) (string, error) {
+
+	t.logger.Debug("entering Refresh")
+
+	token, err := t.source.Refresh(ctx)
+	if err != nil {
+		t.logger.Debug("returning Refresh with error(s)")
+		return "", err
+	}
+
+	t.logger.Debug("returning Refresh")
+	return token.Token, nil
+}
+
+// Claims returns the claims of the current token
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
+
+	t.logger.Debug("entering Claims")
+
+	token, err := t.source.Token(ctx)
+	if err != nil {
+		t.logger.Debug("returning Claims with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning Claims")
+	return common.ParseToken(token.Token)
+}
+
+// AccountID returns Cloud Account ID or error
+func (t *Client) AccountID(ctx context.Context) (string, error) {
+
+	t.logger.Debug("entering AccountID")
+
+	claims, err := t.Claims(ctx)
+	if err != nil {
+		t.logger.Debug("returning AccountID with error(s)")
+		return "", err
+	}
+
+	t.logger.Debug("returning AccountID")
+	return claims.AccountID()
+}
+
+// issueToken issues a new token in the Certificate realm. The certificate is presented by the
+// HTTP client.
+func (t *Client) issueToken(ctx context.Context) (*common.PrismaToken, error) {
+	return t.issuer.Issue(ctx, "Certificate", nil)
+}
@@ -0,0 +1,61 @@
+package token_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_appcred "github.com/aporeto-se/prisma-sdk-go-v2/token/appcred"
+)
+
+func newClient(t *testing.T, server *prismatest.Server) *token_appcred.Client {
+
+	t.Helper()
+
+	credential, err := server.Credential("/tenant", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := token_appcred.NewConfig().
+		SetCredential(credential).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	return client
+}
+
+func TestInvalidate(t *testing.T) {
+
+	server := prismatest.NewTLSServer("/tenant")
+	defer server.Close()
+
+	client := newClient(t, server)
+
+	first, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	// The Certificate realm has no cloud account
+	if _, err := client.AccountID(context.Background()); err == nil {
+		t.Errorf("AccountID returned no error")
+	}
+
+	client.Invalidate()
+
+	second, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token after Invalidate: %v", err)
+	}
+
+	if second == first {
+		t.Errorf("Token after Invalidate returned the invalidated token")
+	}
+
+	if n := server.Count("/issue"); n != 2 {
+		t.Errorf("/issue was called %d times, want 2", n)
+	}
+}
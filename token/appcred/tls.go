@@ -0,0 +1,87 @@
+package token
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// TLSConfig returns the TLS configuration for mutual TLS with the credential. The CA of the
+// credential is trusted in addition to the system roots.
+func TLSConfig(credential *prisma_types.Credential) (*tls.Config, error) {
+
+	certificate, err := decodePEM("Certificate", credential.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	certificateKey, err := decodePEM("CertificateKey", credential.CertificateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPair, err := tls.X509KeyPair(certificate, certificateKey)
+	if err != nil {
+		return nil, fmt.Errorf("credential certificate is invalid: %w", err)
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	if credential.CertificateAuthority != "" {
+
+		certificateAuthority, err := decodePEM("CertificateAuthority", credential.CertificateAuthority)
+		if err != nil {
+			return nil, err
+		}
+
+		if !rootCAs.AppendCertsFromPEM(certificateAuthority) {
+			return nil, fmt.Errorf("credential attribute CertificateAuthority has no certificate")
+		}
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// newHTTPClient returns an HTTP client that authenticates with the TLS configuration. Proxies are
+// taken from the environment like with the default client.
+func newHTTPClient(tlsConfig *tls.Config) *http.Client {
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// decodePEM returns the PEM of a credential attribute. Credential files hold base64 encoded PEM
+// but plain PEM is accepted as well.
+func decodePEM(name, v string) ([]byte, error) {
+
+	if v == "" {
+		return nil, fmt.Errorf("credential attribute %s is required", name)
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(v), "-----BEGIN") {
+		return []byte(v), nil
+	}
+
+	result, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("credential attribute %s is not base64 encoded PEM: %w", name, err)
+	}
+
+	return result, nil
+}
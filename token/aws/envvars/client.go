@@ -19,46 +19,38 @@ type TokenProvider interface {
 
 */
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 
 	"github.com/hashicorp/go-multierror"
 	"go.uber.org/zap"
 
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
 	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
-	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
 )
 
-type req struct {
-	Realm    string `json:"realm"`
-	Validity string `json:"validity"`
-	Quota    int    `json:"quota"`
-	Metadata struct {
-		AccessKeyID     string `json:"accessKeyID"`
-		SecretAccessKey string `json:"secretAccessKey"`
-		Token           string `json:"token"`
-	} `json:"metadata"`
+type awsMetadata struct {
+	AccessKeyID     string `json:"accessKeyID"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	Token           string `json:"token"`
 }
 
-// Client is the Client
+// Client is the Client. It is safe for concurrent use.
 type Client struct {
-	api             string
 	accessKeyID     string
 	secretAccessKey string
 	sessionToken    string
-	httpClient      *http.Client
-
-	token *common.PrismaToken
+	issuer          *common.Issuer
+	source          *common.Source
+	logger          *zap.Logger
 }
 
 // NewClient returns a new client
 func NewClient(config *Config) (*Client, error) {
 
-	zap.L().Debug("entering NewClient")
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
 
 	var errors *multierror.Error
 
@@ -78,130 +70,115 @@ func NewClient(config *Config) (*Client, error) {
 		errors = multierror.Append(errors, fmt.Errorf("attribute sessionToken is required"))
 	}
 
+	if config.Validity < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Validity must not be negative"))
+	}
+
+	if config.Quota < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Quota must not be negative"))
+	}
+
 	err := errors.ErrorOrNil()
 	if err != nil {
-		zap.L().Debug("returning NewClient with error(s)")
+		logger.Debug("returning NewClient with error(s)")
 		return nil, err
 	}
 
-	zap.L().Debug("returning NewClient")
-	return &Client{
-		api:             config.API,
+	client := &Client{
 		accessKeyID:     config.AccessKeyID,
 		secretAccessKey: config.SecretAccessKey,
 		sessionToken:    config.SessionToken,
-		httpClient:      config.GetHTTPClient(),
-	}, nil
+		issuer: &common.Issuer{
+			API:             config.API,
+			Provider:        "aws-envvars",
+			HTTPClient:      config.GetHTTPClient(),
+			RetryPolicy:     config.GetRetryPolicy(),
+			IssueOptions:    config.GetIssueOptions(),
+			Instrumentation: telemetry.New(config.TracerProvider, config.Metrics),
+			Logger:          logger,
+		},
+		logger: logger,
+	}
+
+	cacheKey := common.CacheKey(config.API, "AWSSecurityToken", config.AccessKeyID, config.GetIssueOptions())
+	client.source = common.NewSource(config.Cache, common.StaticKey(cacheKey), client.issueToken, logger)
+
+	logger.Debug("returning NewClient")
+	return client, nil
 }
 
-func (t *Client) initToken(ctx context.Context) error {
-
-	zap.L().Debug("entering initToken")
-
-	if t.token != nil {
-		zap.L().Debug("Token already exist")
-		err := common.TokenExpired(t.token.Claims.Exp)
-		if err != nil {
-			zap.L().Debug("Token is expired, fetching a new one")
-		} else {
-			zap.L().Debug("returning initToken")
-			return nil
-		}
-	} else {
-		zap.L().Debug("Token does not exist; fetching")
-	}
-
-	c := &req{
-		Realm:    "AWSSecurityToken",
-		Validity: "12h",
-	}
-
-	c.Metadata.AccessKeyID = t.accessKeyID
-	c.Metadata.SecretAccessKey = t.secretAccessKey
-	c.Metadata.Token = t.sessionToken
+// issueToken issues a new token for the credentials
+func (t *Client) issueToken(ctx context.Context) (*common.PrismaToken, error) {
+	return t.issuer.Issue(ctx, "AWSSecurityToken", &awsMetadata{
+		AccessKeyID:     t.accessKeyID,
+		SecretAccessKey: t.secretAccessKey,
+		Token:           t.sessionToken,
+	})
+}
 
-	jsonReq, err := json.Marshal(c)
-	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
-	}
+// Token returns token string or error
+func (t *Client) Token(ctx context.Context) (string, error) {
 
-	req, err := http.NewRequestWithContext(ctx, "POST", t.api+"/issue", bytes.NewBuffer(jsonReq))
-	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
-	}
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
+	t.logger.Debug("entering GetToken")
 
-	resp, err := t.httpClient.Do(req)
+	token, err := t.source.Token(ctx)
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		t.logger.Debug("returning GetToken with error(s)")
+		return "", err
 	}
 
-	defer resp.Body.Close()
-
-	respBytes, err := ioutil.ReadAll(resp.Body)
+	t.logger.Debug("returning GetToken")
+	return token.Token, nil
+}
 
-	if err != nil {
-		return err
-	}
+// Invalidate discards the current token so that the next call to Token fetches a new one. The
+// token is also evicted from the cache if one is set.
+func (t *Client) Invalidate() {
+	t.source.Invalidate()
+}
 
-	if resp.StatusCode != 200 {
-		zap.L().Debug("returning initToken with error(s)")
-		return prisma_types.NewAPIError(respBytes)
-	}
+// Refresh returns a new token even if the current one is still valid. The current token is not
+// evicted from the cache; use Invalidate if it was rejected.
+func (t *Client) Refresh(ctx context.Context) (string, error) {
 
-	err = json.Unmarshal(respBytes, &t.token)
-	if err != nil {
-		return err
-	}
-	err = common.TokenExpired(t.token.Claims.Exp)
+	t.logger.Debug("entering Refresh")
 
+	token, err := t.source.Refresh(ctx)
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		t.logger.Debug("returning Refresh with error(s)")
+		return "", err
 	}
 
-	zap.L().Debug("returning initToken")
-	return nil
+	t.logger.Debug("returning Refresh")
+	return token.Token, nil
 }
 
-// Token returns token string or error
-func (t *Client) Token(ctx context.Context) (string, error) {
+// Claims returns the claims of the current token
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
 
-	zap.L().Debug("entering GetToken")
+	t.logger.Debug("entering Claims")
 
-	err := t.initToken(ctx)
+	token, err := t.source.Token(ctx)
 	if err != nil {
-		zap.L().Debug("returning GetToken with error(s)")
-		return "", err
+		t.logger.Debug("returning Claims with error(s)")
+		return nil, err
 	}
 
-	zap.L().Debug("returning GetToken")
-	return t.token.Token, nil
+	t.logger.Debug("returning Claims")
+	return common.ParseToken(token.Token)
 }
 
 // AccountID returns Cloud Account ID or error
 func (t *Client) AccountID(ctx context.Context) (string, error) {
 
-	zap.L().Debug("entering AccountID")
+	t.logger.Debug("entering AccountID")
 
-	err := t.initToken(ctx)
+	claims, err := t.Claims(ctx)
 	if err != nil {
-		zap.L().Debug("returning AccountID with error(s)")
+		t.logger.Debug("returning AccountID with error(s)")
 		return "", err
 	}
 
-	// We use the Data.Organization attribute for AWS
-	result := t.token.Claims.Data.Organization
-
-	if result == "" {
-		zap.L().Debug("returning AccountID with error(s)")
-		return "", fmt.Errorf("unable to get cloud account ID")
-	}
-
-	zap.L().Debug("returning AccountID")
-	return result, nil
+	t.logger.Debug("returning AccountID")
+	return claims.AccountID()
 }
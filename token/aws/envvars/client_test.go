@@ -0,0 +1,126 @@
+package token_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_envvars "github.com/aporeto-se/prisma-sdk-go-v2/token/aws/envvars"
+)
+
+func newClient(t *testing.T, server *prismatest.Server) *token_envvars.Client {
+
+	t.Helper()
+
+	client, err := token_envvars.NewConfig().
+		SetAPI(server.URL).
+		SetAccessKeyID("AKIAEXAMPLE").
+		SetSecretAccessKey("secret").
+		SetSessionToken("session").
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	return client
+}
+
+func TestInvalidate(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client := newClient(t, server)
+
+	first, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	accountID, err := client.AccountID(context.Background())
+	if err != nil || accountID != "123456789012" {
+		t.Errorf("AccountID returned %q, %v", accountID, err)
+	}
+
+	client.Invalidate()
+
+	second, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token after Invalidate: %v", err)
+	}
+
+	if second == first {
+		t.Errorf("Token after Invalidate returned the invalidated token")
+	}
+
+	if n := server.Count("/issue"); n != 2 {
+		t.Errorf("/issue was called %d times, want 2", n)
+	}
+}
+
+func TestIssueOptions(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client, err := token_envvars.NewConfig().
+		SetAPI(server.URL).
+		SetAccessKeyID("AKIAEXAMPLE").
+		SetSecretAccessKey("secret").
+		SetSessionToken("session").
+		SetQuota(3).
+		SetAudience("audience").
+		SetRestrictedNamespace("/tenant/child").
+		SetRestrictedNetworks("10.0.0.0/8").
+		SetRestrictedPermissions("namespaces:get").
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// The server must accept the validity in the format of time.Duration
+	_, err = client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	var body struct {
+		Realm                 string   `json:"realm"`
+		Validity              string   `json:"validity"`
+		Quota                 int      `json:"quota"`
+		Audience              string   `json:"audience"`
+		RestrictedNamespace   string   `json:"restrictedNamespace"`
+		RestrictedNetworks    []string `json:"restrictedNetworks"`
+		RestrictedPermissions []string `json:"restrictedPermissions"`
+		Metadata              struct {
+			AccessKeyID     string `json:"accessKeyID"`
+			SecretAccessKey string `json:"secretAccessKey"`
+			Token           string `json:"token"`
+		} `json:"metadata"`
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Path != "/issue" {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+
+	err = json.Unmarshal(requests[0].Body, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body.Realm != "AWSSecurityToken" ||
+		body.Validity != "12h0m0s" ||
+		body.Quota != 3 ||
+		body.Audience != "audience" ||
+		body.RestrictedNamespace != "/tenant/child" ||
+		len(body.RestrictedNetworks) != 1 || body.RestrictedNetworks[0] != "10.0.0.0/8" ||
+		len(body.RestrictedPermissions) != 1 || body.RestrictedPermissions[0] != "namespaces:get" {
+		t.Errorf("unexpected /issue request %s", requests[0].Body)
+	}
+
+	if body.Metadata.AccessKeyID != "AKIAEXAMPLE" || body.Metadata.SecretAccessKey != "secret" || body.Metadata.Token != "session" {
+		t.Errorf("unexpected metadata in /issue request %s", requests[0].Body)
+	}
+}
@@ -1,7 +1,6 @@
 package token
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,22 +8,18 @@ import (
 	"net/http"
 	"time"
 
-	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
 	"github.com/hashicorp/go-multierror"
 	"go.uber.org/zap"
 
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
 	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
 )
 
-type tokenRequest struct {
-	Realm    string `json:"realm"`
-	Validity string `json:"validity"`
-	Quota    int    `json:"quota"`
-	Metadata struct {
-		AccessKeyID     string `json:"accessKeyID"`
-		SecretAccessKey string `json:"secretAccessKey"`
-		Token           string `json:"token"`
-	} `json:"metadata"`
+type awsMetadata struct {
+	AccessKeyID     string `json:"accessKeyID"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	Token           string `json:"token"`
 }
 
 type awsStsToken struct {
@@ -37,18 +32,23 @@ type awsStsToken struct {
 	Expiration      time.Time `json:"expiration,omitempty" yaml:"expiration"`
 }
 
-// Client is the Client
+// Client is the Client. It is safe for concurrent use.
 type Client struct {
-	api        string
-	httpClient *http.Client
-
-	token *common.PrismaToken
+	api          string
+	httpClient   *http.Client
+	retryPolicy  *retry.Policy
+	issueOptions common.IssueOptions
+	issuer       *common.Issuer
+	source       *common.Source
+	logger       *zap.Logger
 }
 
 // NewClient returns a new client
 func NewClient(config *Config) (*Client, error) {
 
-	zap.L().Debug("entering NewClient")
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
 
 	var errors *multierror.Error
 
@@ -56,181 +56,190 @@ func NewClient(config *Config) (*Client, error) {
 		errors = multierror.Append(errors, fmt.Errorf("attribute API is required"))
 	}
 
+	if config.Validity < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Validity must not be negative"))
+	}
+
+	if config.Quota < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Quota must not be negative"))
+	}
+
 	err := errors.ErrorOrNil()
 	if err != nil {
-		zap.L().Debug("returning NewClient with error(s)")
+		logger.Debug("returning NewClient with error(s)")
 		return nil, err
 	}
 
-	zap.L().Debug("returning NewClient")
-	return &Client{
-		api:        config.API,
-		httpClient: config.GetHTTPClient(),
-	}, nil
+	client := &Client{
+		api:          config.API,
+		httpClient:   config.GetHTTPClient(),
+		retryPolicy:  config.GetRetryPolicy(),
+		issueOptions: config.GetIssueOptions(),
+		issuer: &common.Issuer{
+			API:             config.API,
+			Provider:        "aws-meta",
+			HTTPClient:      config.GetHTTPClient(),
+			RetryPolicy:     config.GetRetryPolicy(),
+			IssueOptions:    config.GetIssueOptions(),
+			Instrumentation: telemetry.New(config.TracerProvider, config.Metrics),
+			Logger:          logger,
+		},
+		logger: logger,
+	}
+
+	client.source = common.NewSource(config.Cache, client.cacheKey, client.issueToken, logger)
+
+	logger.Debug("returning NewClient")
+	return client, nil
 }
 
 // Token returns token string or error
 func (t *Client) Token(ctx context.Context) (string, error) {
 
-	zap.L().Debug("entering Token")
+	t.logger.Debug("entering Token")
 
-	err := t.initToken(ctx)
+	token, err := t.source.Token(ctx)
 	if err != nil {
-		zap.L().Debug("returning Token with error(s)")
-		return "", nil
+		t.logger.Debug("returning Token with error(s)")
+		return "", err
 	}
 
-	zap.L().Debug("returning Token")
-	return t.token.Token, nil
+	t.logger.Debug("returning Token")
+	return token.Token, nil
 }
 
-// AccountID returns Cloud Account ID or error
-func (t *Client) AccountID(ctx context.Context) (string, error) {
+// Invalidate discards the current token so that the next call to Token fetches a new one. The
+// token is also evicted from the cache if one is set.
+func (t *Client) Invalidate() {
+	t.source.Invalidate()
+}
+
+// Refresh returns a new token even if the current one is still valid. The current token is not
+// evicted from the cache; use Invalidate if it was rejected.
+func (t *Client) Refresh(ctx context.Context) (string, error) {
 
-	zap.L().Debug("entering AccountID")
+	t.logger.Debug("entering Refresh")
 
-	err := t.initToken(ctx)
+	token, err := t.source.Refresh(ctx)
 	if err != nil {
-		zap.L().Debug("returning AccountID with error(s)")
+		t.logger.Debug("returning Refresh with error(s)")
 		return "", err
 	}
 
-	// We use the Data.Organization attribute for AWS
-	result := t.token.Claims.Data.Organization
+	t.logger.Debug("returning Refresh")
+	return token.Token, nil
+}
+
+// Claims returns the claims of the current token
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
 
-	if result == "" {
-		zap.L().Debug("returning AccountID with error(s)")
-		return "", fmt.Errorf("unable to get cloud account ID")
+	t.logger.Debug("entering Claims")
+
+	token, err := t.source.Token(ctx)
+	if err != nil {
+		t.logger.Debug("returning Claims with error(s)")
+		return nil, err
 	}
 
-	zap.L().Debug("returning AccountID")
-	return result, nil
+	t.logger.Debug("returning Claims")
+	return common.ParseToken(token.Token)
 }
 
-func (t *Client) initToken(ctx context.Context) error {
-
-	zap.L().Debug("initToken enter")
+// AccountID returns Cloud Account ID or error
+func (t *Client) AccountID(ctx context.Context) (string, error) {
 
-	if t.token != nil {
-		zap.L().Debug("existing token found in cache")
-		err := common.TokenExpired(t.token.Claims.Exp)
-		if err == nil {
-			zap.L().Debug("token in cache is good")
-			return nil
-		}
-		zap.L().Debug("token in cache is expired")
-	} else {
-		zap.L().Debug("no existing token in cache")
-	}
+	t.logger.Debug("entering AccountID")
 
-	role, err := t.getAwsRole(ctx)
+	claims, err := t.Claims(ctx)
 	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
+		t.logger.Debug("returning AccountID with error(s)")
+		return "", err
 	}
 
-	sessionToken, err := t.getAwsSessionToken(ctx)
-	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
-	}
+	t.logger.Debug("returning AccountID")
+	return claims.AccountID()
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", "http://169.254.169.254/latest/meta-data/iam/security-credentials/"+role, nil)
-	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
-	}
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("X-aws-ec2-metadata-token", sessionToken)
+// cacheKey returns the cache key of the tokens issued for the role of the instance
+func (t *Client) cacheKey(ctx context.Context) (string, error) {
 
-	resp, err := t.httpClient.Do(req)
+	role, err := t.getAwsRole(ctx)
 	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
+		return "", err
 	}
 
-	defer resp.Body.Close()
+	// The instance profile may be replaced while the instance is running
+	return common.CacheKey(t.api, "AWSSecurityToken", role, t.issueOptions), nil
+}
 
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
-	}
+// issueToken issues a new token for the credentials of the role of the instance
+func (t *Client) issueToken(ctx context.Context) (*common.PrismaToken, error) {
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf(string(respBytes))
-	}
+	t.logger.Debug("entering issueToken")
 
-	var awsStsToken *awsStsToken
-	err = json.Unmarshal(respBytes, &awsStsToken)
+	role, err := t.getAwsRole(ctx)
 	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
-	}
-
-	c := &tokenRequest{
-		Realm:    "AWSSecurityToken",
-		Validity: "12h",
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
 
-	c.Metadata.AccessKeyID = awsStsToken.AccessKeyID
-	c.Metadata.SecretAccessKey = awsStsToken.SecretAccessKey
-	c.Metadata.Token = awsStsToken.Token
-
-	jsonReq, err := json.Marshal(c)
+	sessionToken, err := t.getAwsSessionToken(ctx)
 	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
 
-	req, err = http.NewRequestWithContext(ctx, "POST", t.api+"/issue", bytes.NewBuffer(jsonReq))
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://169.254.169.254/latest/meta-data/iam/security-credentials/"+role, nil)
 	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-aws-ec2-metadata-token", sessionToken)
 
-	resp, err = t.httpClient.Do(req)
+	resp, err := t.retryPolicy.Do(req, t.httpClient.Do)
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 
-	respBytes, err = ioutil.ReadAll(resp.Body)
-
+	respBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
 
 	if resp.StatusCode != 200 {
-		zap.L().Debug("returning initToken with error(s)")
-		return prisma_types.NewAPIError(respBytes)
+		return nil, fmt.Errorf(string(respBytes))
 	}
 
-	err = json.Unmarshal(respBytes, &t.token)
+	var awsStsToken *awsStsToken
+	err = json.Unmarshal(respBytes, &awsStsToken)
 	if err != nil {
-		zap.L().Debug("initToken returning with error(s)")
-		return err
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
-	err = common.TokenExpired(t.token.Claims.Exp)
 
+	token, err := t.issuer.Issue(ctx, "AWSSecurityToken", &awsMetadata{
+		AccessKeyID:     awsStsToken.AccessKeyID,
+		SecretAccessKey: awsStsToken.SecretAccessKey,
+		Token:           awsStsToken.Token,
+	})
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
 
-	zap.L().Debug("returning initToken")
-	return nil
+	t.logger.Debug("returning issueToken")
+	return token, nil
 }
 
 func (t *Client) getAwsSessionToken(ctx context.Context) (string, error) {
 
-	zap.L().Debug("getAwsSessionToken() enter")
+	t.logger.Debug("getAwsSessionToken() enter")
 
 	req, err := http.NewRequestWithContext(ctx, "PUT", "http://169.254.169.254/latest/api/token", nil)
 	if err != nil {
@@ -240,9 +249,9 @@ func (t *Client) getAwsSessionToken(ctx context.Context) (string, error) {
 	req.Header.Add("Content-Type", "application/text")
 	req.Header.Add("X-aws-ec2-metadata-token-ttl-seconds", "21600")
 
-	resp, err := t.httpClient.Do(req)
+	resp, err := t.retryPolicy.Do(req, t.httpClient.Do)
 	if err != nil {
-		zap.L().Error("Retrieving AWS Session Token: failed")
+		t.logger.Error("Retrieving AWS Session Token: failed")
 		return "", err
 	}
 
@@ -251,22 +260,22 @@ func (t *Client) getAwsSessionToken(ctx context.Context) (string, error) {
 	respBytes, err := ioutil.ReadAll(resp.Body)
 
 	if err != nil {
-		zap.L().Error("Retrieving AWS Session Token: failed")
+		t.logger.Error("Retrieving AWS Session Token: failed")
 		return "", err
 	}
 
 	if resp.StatusCode != 200 {
-		zap.L().Error("Retrieving AWS Session Token: failed")
+		t.logger.Error("Retrieving AWS Session Token: failed")
 		return "", fmt.Errorf(string(respBytes))
 	}
 
-	zap.L().Debug("getAwsSessionToken() return")
+	t.logger.Debug("getAwsSessionToken() return")
 	return string(respBytes), nil
 }
 
 func (t *Client) getAwsRole(ctx context.Context) (string, error) {
 
-	zap.L().Debug("getAwsRole() enter")
+	t.logger.Debug("getAwsRole() enter")
 
 	req, err := http.NewRequestWithContext(ctx, "GET", "http://169.254.169.254/latest/meta-data/iam/security-credentials/", nil)
 	if err != nil {
@@ -275,7 +284,7 @@ func (t *Client) getAwsRole(ctx context.Context) (string, error) {
 	req.Header.Add("Accept", "application/text")
 	req.Header.Add("Content-Type", "application/text")
 
-	resp, err := t.httpClient.Do(req)
+	resp, err := t.retryPolicy.Do(req, t.httpClient.Do)
 	if err != nil {
 		return "", err
 	}
@@ -291,6 +300,6 @@ func (t *Client) getAwsRole(ctx context.Context) (string, error) {
 		return "", fmt.Errorf(string(respBytes))
 	}
 
-	zap.L().Debug("getAwsRole() return")
+	t.logger.Debug("getAwsRole() return")
 	return string(respBytes), nil
 }
@@ -0,0 +1,184 @@
+package token_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_meta "github.com/aporeto-se/prisma-sdk-go-v2/token/aws/meta"
+	token_cache "github.com/aporeto-se/prisma-sdk-go-v2/token/cache"
+)
+
+// metadataServer is a fake of the instance metadata service
+type metadataServer struct {
+	*httptest.Server
+	mutex sync.Mutex
+	role  string
+}
+
+func newMetadataServer() *metadataServer {
+
+	t := &metadataServer{role: "test-role"}
+
+	t.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		t.mutex.Lock()
+		role := t.role
+		t.mutex.Unlock()
+
+		switch r.URL.Path {
+
+		case "/latest/api/token":
+			fmt.Fprint(w, "session")
+
+		case "/latest/meta-data/iam/security-credentials/":
+			fmt.Fprint(w, role)
+
+		case "/latest/meta-data/iam/security-credentials/" + role:
+			fmt.Fprintf(w, `{"Code":"Success","AccessKeyId":"AKIA%s","SecretAccessKey":"secret","Token":"token"}`, role)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	return t
+}
+
+// setRole replaces the role of the instance profile
+func (t *metadataServer) setRole(role string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.role = role
+}
+
+// httpClient returns a client that sends the requests for the metadata service to the fake
+func (t *metadataServer) httpClient() *http.Client {
+
+	target, _ := url.Parse(t.URL)
+
+	return &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "169.254.169.254" {
+				req = req.Clone(req.Context())
+				req.URL.Scheme = target.Scheme
+				req.URL.Host = target.Host
+			}
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newClient(t *testing.T, server *prismatest.Server, metadata *metadataServer) *token_meta.Client {
+
+	t.Helper()
+
+	client, err := token_meta.NewConfig().
+		SetAPI(server.URL).
+		SetHTTPClient(metadata.httpClient()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	return client
+}
+
+func TestInvalidate(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	metadata := newMetadataServer()
+	defer metadata.Close()
+
+	client := newClient(t, server, metadata)
+
+	first, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	accountID, err := client.AccountID(context.Background())
+	if err != nil || accountID != "123456789012" {
+		t.Errorf("AccountID returned %q, %v", accountID, err)
+	}
+
+	client.Invalidate()
+
+	second, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token after Invalidate: %v", err)
+	}
+
+	if second == first {
+		t.Errorf("Token after Invalidate returned the invalidated token")
+	}
+
+	if n := server.Count("/issue"); n != 2 {
+		t.Errorf("/issue was called %d times, want 2", n)
+	}
+}
+
+func TestCacheKeyedOnRole(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	metadata := newMetadataServer()
+	defer metadata.Close()
+
+	cache, err := token_cache.NewConfig().
+		SetDir(t.TempDir()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// Each client stands for a process that shares the cache
+	token := func() string {
+
+		client, err := token_meta.NewConfig().
+			SetAPI(server.URL).
+			SetHTTPClient(metadata.httpClient()).
+			SetCache(cache).
+			Build()
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+
+		token, err := client.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+
+		return token
+	}
+
+	first := token()
+
+	if token() != first {
+		t.Errorf("token of the same role was not read from the cache")
+	}
+
+	metadata.setRole("other-role")
+
+	if token() == first {
+		t.Errorf("token of another role was read from the cache")
+	}
+
+	if n := server.Count("/issue"); n != 2 {
+		t.Errorf("/issue was called %d times, want 2", n)
+	}
+}
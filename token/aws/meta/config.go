@@ -2,14 +2,31 @@ package token
 
 import (
 	"net/http"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
 )
 
 // Config config
 type Config struct {
-	API        string
-	HTTPClient *http.Client
+	API                   string
+	HTTPClient            *http.Client
+	RetryPolicy           *retry.Policy
+	Validity              time.Duration
+	Quota                 int
+	Audience              string
+	RestrictedNamespace   string
+	RestrictedNetworks    []string
+	RestrictedPermissions []string
+	Logger                *zap.Logger
+	TracerProvider        trace.TracerProvider
+	Metrics               telemetry.Metrics
+	Cache                 common.TokenCache
 }
 
 // NewConfig returns new Config
@@ -34,14 +51,125 @@ func (t *Config) GetHTTPClient() *http.Client {
 
 	if t.HTTPClient == nil {
 		t.HTTPClient = &http.Client{}
-		zap.L().Debug("HTTPClient created new")
+		t.GetLogger().Debug("HTTPClient created new")
 	} else {
-		zap.L().Debug("HTTPClient set from config")
+		t.GetLogger().Debug("HTTPClient set from config")
 	}
 
 	return t.HTTPClient
 }
 
+// SetRetryPolicy sets entity and returns self
+func (t *Config) SetRetryPolicy(retryPolicy *retry.Policy) *Config {
+	t.RetryPolicy = retryPolicy
+	return t
+}
+
+// GetRetryPolicy returns entity. If entity is nil retry.NewPolicy() is returned. The policy logs to
+// the logger of the config unless it has its own.
+func (t *Config) GetRetryPolicy() *retry.Policy {
+
+	if t.RetryPolicy == nil {
+		return retry.NewPolicy().SetLogger(t.GetLogger())
+	}
+
+	return t.RetryPolicy.WithLogger(t.GetLogger())
+}
+
+// SetValidity sets the requested lifetime of the token and returns self. If not set
+// common.DefaultValidity is used.
+func (t *Config) SetValidity(validity time.Duration) *Config {
+	t.Validity = validity
+	return t
+}
+
+// SetQuota sets the number of times the token may be used and returns self. If not set the
+// token may be used without limit.
+func (t *Config) SetQuota(quota int) *Config {
+	t.Quota = quota
+	return t
+}
+
+// SetAudience sets the audience of the token and returns self
+func (t *Config) SetAudience(audience string) *Config {
+	t.Audience = audience
+	return t
+}
+
+// SetRestrictedNamespace restricts the token to the namespace and its children and returns self
+func (t *Config) SetRestrictedNamespace(restrictedNamespace string) *Config {
+	t.RestrictedNamespace = restrictedNamespace
+	return t
+}
+
+// SetRestrictedNetworks restricts the token to requests from the networks (CIDRs) and returns self
+func (t *Config) SetRestrictedNetworks(restrictedNetworks ...string) *Config {
+	t.RestrictedNetworks = restrictedNetworks
+	return t
+}
+
+// SetRestrictedPermissions restricts the token to the permissions (for example
+// "namespaces:get") and returns self
+func (t *Config) SetRestrictedPermissions(restrictedPermissions ...string) *Config {
+	t.RestrictedPermissions = restrictedPermissions
+	return t
+}
+
+// GetIssueOptions returns the parameters of the /issue request
+func (t *Config) GetIssueOptions() common.IssueOptions {
+
+	validity := t.Validity
+	if validity == 0 {
+		validity = common.DefaultValidity
+	}
+
+	return common.IssueOptions{
+		Validity:              validity.String(),
+		Quota:                 t.Quota,
+		Audience:              t.Audience,
+		RestrictedNamespace:   t.RestrictedNamespace,
+		RestrictedNetworks:    t.RestrictedNetworks,
+		RestrictedPermissions: t.RestrictedPermissions,
+	}
+}
+
+// SetLogger sets entity and returns self. If not set nothing is logged.
+func (t *Config) SetLogger(logger *zap.Logger) *Config {
+	t.Logger = logger
+	return t
+}
+
+// GetLogger returns entity. If entity is nil a no-op logger is returned.
+func (t *Config) GetLogger() *zap.Logger {
+
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+
+	return t.Logger
+}
+
+// SetTracerProvider sets interface and returns self. Every /issue exchange is wrapped in a span.
+// If not set nothing is traced.
+func (t *Config) SetTracerProvider(tracerProvider trace.TracerProvider) *Config {
+	t.TracerProvider = tracerProvider
+	return t
+}
+
+// SetMetrics sets interface and returns self. If not set nothing is measured.
+func (t *Config) SetMetrics(metrics telemetry.Metrics) *Config {
+	t.Metrics = metrics
+	return t
+}
+
+// SetCache sets interface and returns self. Issued tokens are stored in the cache per role of the
+// instance profile and reused while they are valid, also by other processes. If not set tokens
+// are only kept in memory.
+func (t *Config) SetCache(cache common.TokenCache) *Config {
+	t.Cache = cache
+	return t
+}
+
 // Build returns entity
 func (t *Config) Build() (*Client, error) {
 	return NewClient(t)
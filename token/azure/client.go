@@ -0,0 +1,286 @@
+package token
+
+/*
+
+This implements the TokenProvider Interface and provides Prisma tokens using Azure managed
+identity tokens. This implementation should run within an Azure environment where it can obtain
+a managed identity token, either from the Instance Metadata Service of a VM or from the identity
+endpoint of App Service and Functions (IDENTITY_ENDPOINT and IDENTITY_HEADER).
+
+type TokenProvider interface {
+	Token(context.Context) (string, error)
+	AccountID(ctx context.Context) (string, error)
+}
+
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+const (
+	metadataTokenPath       = "/metadata/identity/oauth2/token"
+	metadataAPIVersion      = "2018-02-01"
+	identityEndpointVersion = "2019-08-01"
+)
+
+type identityMetadata struct {
+	Token string `json:"token"`
+}
+
+type azureToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresOn   string `json:"expires_on"`
+	Resource    string `json:"resource"`
+	TokenType   string `json:"token_type"`
+	ClientID    string `json:"client_id"`
+}
+
+// Client the token client. It is safe for concurrent use.
+type Client struct {
+	api              string
+	httpClient       *http.Client
+	retryPolicy      *retry.Policy
+	metadataURL      string
+	identityEndpoint string
+	identityHeader   string
+	clientID         string
+	resource         string
+	issuer           *common.Issuer
+	source           *common.Source
+	logger           *zap.Logger
+}
+
+// NewClient returns new Client
+func NewClient(config *Config) (*Client, error) {
+
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
+
+	var errors *multierror.Error
+
+	if config.API == "" {
+		errors = multierror.Append(errors, fmt.Errorf("attribute API is required"))
+	}
+
+	if config.Validity < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Validity must not be negative"))
+	}
+
+	if config.Quota < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Quota must not be negative"))
+	}
+
+	identityEndpoint, identityHeader := config.GetIdentityEndpoint()
+
+	if identityEndpoint != "" && identityHeader == "" {
+		errors = multierror.Append(errors, fmt.Errorf("attribute IdentityHeader is required with IdentityEndpoint"))
+	}
+
+	err := errors.ErrorOrNil()
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	client := &Client{
+		api:              config.API,
+		httpClient:       config.GetHTTPClient(),
+		retryPolicy:      config.GetRetryPolicy(),
+		metadataURL:      config.GetMetadataURL(),
+		identityEndpoint: identityEndpoint,
+		identityHeader:   identityHeader,
+		clientID:         config.ClientID,
+		resource:         config.GetResource(),
+		issuer: &common.Issuer{
+			API:             config.API,
+			Provider:        "azure",
+			HTTPClient:      config.GetHTTPClient(),
+			RetryPolicy:     config.GetRetryPolicy(),
+			IssueOptions:    config.GetIssueOptions(),
+			Instrumentation: telemetry.New(config.TracerProvider, config.Metrics),
+			Logger:          logger,
+		},
+		logger: logger,
+	}
+
+	identity := strings.Join([]string{identityEndpoint, config.ClientID, config.GetResource()}, " ")
+	cacheKey := common.CacheKey(config.API, "AzureIdentityToken", identity, config.GetIssueOptions())
+	client.source = common.NewSource(config.Cache, common.StaticKey(cacheKey), client.issueToken, logger)
+
+	logger.Debug("returning NewClient")
+	return client, nil
+}
+
+// Token returns token string or error
+func (t *Client) Token(ctx context.Context) (string, error) {
+
+	t.logger.Debug("entering Token")
+
+	token, err := t.source.Token(ctx)
+	if err != nil {
+		t.logger.Debug("returning Token with error(s)")
+		return "", err
+	}
+
+	t.logger.Debug("returning Token")
+	return token.Token, nil
+}
+
+// Invalidate discards the current token so that the next call to Token fetches a new one. The
+// token is also evicted from the cache if one is set.
+func (t *Client) Invalidate() {
+	t.source.Invalidate()
+}
+
+// Refresh returns a new token even if the current one is still valid. The current token is not
+// evicted from the cache; use Invalidate if it was rejected.
+func (t *Client) Refresh(ctx context.Context) (string, error) {
+
+	t.logger.Debug("entering Refresh")
+
+	token, err := t.source.Refresh(ctx)
+	if err != nil {
+		t.logger.Debug("returning Refresh with error(s)")
+		return "", err
+	}
+
+	t.logger.Debug("returning Refresh")
+	return token.Token, nil
+}
+
+// Claims returns the claims of the current token
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
+
+	t.logger.Debug("entering Claims")
+
+	token, err := t.source.Token(ctx)
+	if err != nil {
+		t.logger.Debug("returning Claims with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning Claims")
+	return common.ParseToken(token.Token)
+}
+
+// AccountID returns Cloud Account ID or error
+func (t *Client) AccountID(ctx context.Context) (string, error) {
+
+	t.logger.Debug("entering AccountID")
+
+	claims, err := t.Claims(ctx)
+	if err != nil {
+		t.logger.Debug("returning AccountID with error(s)")
+		return "", err
+	}
+
+	t.logger.Debug("returning AccountID")
+	return claims.AccountID()
+}
+
+// issueToken issues a new token for the managed identity
+func (t *Client) issueToken(ctx context.Context) (*common.PrismaToken, error) {
+
+	t.logger.Debug("entering issueToken")
+
+	cloudToken, err := t.getAzureToken(ctx)
+	if err != nil {
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
+	}
+
+	token, err := t.issuer.Issue(ctx, "AzureIdentityToken", &identityMetadata{
+		Token: cloudToken,
+	})
+	if err != nil {
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
+	}
+
+	t.logger.Debug("returning issueToken")
+	return token, nil
+}
+
+// getAzureToken returns a managed identity token from the identity endpoint if one is configured
+// and from the Instance Metadata Service otherwise
+func (t *Client) getAzureToken(ctx context.Context) (string, error) {
+
+	t.logger.Debug("entering getAzureToken")
+
+	query := url.Values{}
+	query.Set("resource", t.resource)
+	if t.clientID != "" {
+		query.Set("client_id", t.clientID)
+	}
+
+	var endpoint, header, value string
+
+	if t.identityEndpoint != "" {
+		query.Set("api-version", identityEndpointVersion)
+		endpoint = t.identityEndpoint
+		header = "X-IDENTITY-HEADER"
+		value = t.identityHeader
+	} else {
+		query.Set("api-version", metadataAPIVersion)
+		endpoint = t.metadataURL + metadataTokenPath
+		header = "Metadata"
+		value = "true"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		t.logger.Debug("returning getAzureToken with error(s)")
+		return "", err
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add(header, value)
+
+	resp, err := t.retryPolicy.Do(req, t.httpClient.Do)
+	if err != nil {
+		t.logger.Error("Retrieving Azure identity token: failed")
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.logger.Error("Retrieving Azure identity token: failed")
+		return "", err
+	}
+
+	if resp.StatusCode != 200 {
+		t.logger.Error("Retrieving Azure identity token: failed")
+		return "", fmt.Errorf("identity token request returned status code %d: %s", resp.StatusCode, string(respBytes))
+	}
+
+	var azureToken *azureToken
+	err = json.Unmarshal(respBytes, &azureToken)
+	if err != nil {
+		t.logger.Debug("returning getAzureToken with error(s)")
+		return "", err
+	}
+
+	if azureToken == nil || azureToken.AccessToken == "" {
+		t.logger.Debug("returning getAzureToken with error(s)")
+		return "", fmt.Errorf("identity token response has no access_token")
+	}
+
+	t.logger.Debug("returning getAzureToken")
+	return azureToken.AccessToken, nil
+}
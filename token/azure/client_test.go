@@ -0,0 +1,235 @@
+package token_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_azure "github.com/aporeto-se/prisma-sdk-go-v2/token/azure"
+)
+
+// metadataServer is a fake of the Instance Metadata Service and of the identity endpoint of App
+// Service at /msi/token. It records the requests for identity tokens.
+type metadataServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []*http.Request
+}
+
+func newMetadataServer() *metadataServer {
+
+	t := &metadataServer{}
+
+	t.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.URL.Path != "/metadata/identity/oauth2/token" && r.URL.Path != "/msi/token" {
+			http.NotFound(w, r)
+			return
+		}
+
+		t.mutex.Lock()
+		t.requests = append(t.requests, r.Clone(context.Background()))
+		t.mutex.Unlock()
+
+		fmt.Fprint(w, `{"access_token":"identity-token","token_type":"Bearer"}`)
+	}))
+
+	return t
+}
+
+// lastRequest returns the last request for an identity token
+func (t *metadataServer) lastRequest(test *testing.T) *http.Request {
+
+	test.Helper()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.requests) == 0 {
+		test.Fatal("no identity token was requested")
+	}
+
+	return t.requests[len(t.requests)-1]
+}
+
+func newClient(t *testing.T, server *prismatest.Server, metadata *metadataServer) *token_azure.Client {
+
+	t.Helper()
+
+	client, err := token_azure.NewConfig().
+		SetAPI(server.URL).
+		SetMetadataURL(metadata.URL).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	return client
+}
+
+func TestInvalidate(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	metadata := newMetadataServer()
+	defer metadata.Close()
+
+	client := newClient(t, server, metadata)
+
+	first, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	accountID, err := client.AccountID(context.Background())
+	if err != nil || accountID != "123456789012" {
+		t.Errorf("AccountID returned %q, %v", accountID, err)
+	}
+
+	client.Invalidate()
+
+	second, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token after Invalidate: %v", err)
+	}
+
+	if second == first {
+		t.Errorf("Token after Invalidate returned the invalidated token")
+	}
+
+	if n := server.Count("/issue"); n != 2 {
+		t.Errorf("/issue was called %d times, want 2", n)
+	}
+}
+
+func TestMetadataRequest(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	metadata := newMetadataServer()
+	defer metadata.Close()
+
+	_, err := newClient(t, server, metadata).Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	req := metadata.lastRequest(t)
+
+	if req.URL.Path != "/metadata/identity/oauth2/token" || req.Header.Get("Metadata") != "true" {
+		t.Errorf("unexpected request %s with header %v", req.URL, req.Header)
+	}
+
+	query := req.URL.Query()
+
+	if query.Get("api-version") != "2018-02-01" ||
+		query.Get("resource") != token_azure.DefaultResource ||
+		query.Has("client_id") {
+		t.Errorf("unexpected query %s", req.URL.RawQuery)
+	}
+}
+
+func TestIdentityEndpointRequest(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	metadata := newMetadataServer()
+	defer metadata.Close()
+
+	// App Service and Functions set the identity endpoint and its secret in the environment
+	t.Setenv(token_azure.IdentityEndpointEnv, metadata.URL+"/msi/token")
+	t.Setenv(token_azure.IdentityHeaderEnv, "secret")
+
+	client, err := token_azure.NewConfig().
+		SetAPI(server.URL).
+		SetMetadataURL("http://metadata.invalid").
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	_, err = client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	req := metadata.lastRequest(t)
+
+	if req.URL.Path != "/msi/token" ||
+		req.Header.Get("X-IDENTITY-HEADER") != "secret" ||
+		req.Header.Get("Metadata") != "" {
+		t.Errorf("unexpected request %s with header %v", req.URL, req.Header)
+	}
+
+	if query := req.URL.Query(); query.Get("api-version") != "2019-08-01" {
+		t.Errorf("unexpected query %s", req.URL.RawQuery)
+	}
+}
+
+func TestUserAssignedIdentityRequest(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	metadata := newMetadataServer()
+	defer metadata.Close()
+
+	client, err := token_azure.NewConfig().
+		SetAPI(server.URL).
+		SetMetadataURL(metadata.URL).
+		SetClientID("00000000-0000-0000-0000-000000000001").
+		SetResource("api://prisma").
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	_, err = client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	req := metadata.lastRequest(t)
+
+	if query := req.URL.Query(); query.Get("client_id") != "00000000-0000-0000-0000-000000000001" ||
+		query.Get("resource") != "api://prisma" {
+		t.Errorf("unexpected query %s", req.URL.RawQuery)
+	}
+}
+
+func TestAccountIDFromSubscription(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	server.SetAccountID("00000000-0000-0000-0000-000000000002")
+
+	metadata := newMetadataServer()
+	defer metadata.Close()
+
+	client := newClient(t, server, metadata)
+
+	claims, err := client.Claims(context.Background())
+	if err != nil {
+		t.Fatalf("Claims: %v", err)
+	}
+
+	// Only the subscriptionid claim holds the account of an Azure token
+	if claims.Realm != "AzureIdentityToken" ||
+		claims.Data["subscriptionid"] != "00000000-0000-0000-0000-000000000002" ||
+		claims.Data["organization"] != "" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+
+	accountID, err := client.AccountID(context.Background())
+	if err != nil || accountID != "00000000-0000-0000-0000-000000000002" {
+		t.Errorf("AccountID returned %q, %v", accountID, err)
+	}
+}
@@ -0,0 +1,258 @@
+package token
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+const (
+
+	// DefaultMetadataURL is the base URL of the Azure Instance Metadata Service
+	DefaultMetadataURL = "http://169.254.169.254"
+
+	// DefaultResource is the resource the identity token is requested for
+	DefaultResource = "https://management.azure.com/"
+
+	// IdentityEndpointEnv enviroment variable set by App Service and Functions
+	IdentityEndpointEnv = "IDENTITY_ENDPOINT"
+
+	// IdentityHeaderEnv enviroment variable set by App Service and Functions
+	IdentityHeaderEnv = "IDENTITY_HEADER"
+)
+
+// Config config
+type Config struct {
+	API                   string
+	HTTPClient            *http.Client
+	RetryPolicy           *retry.Policy
+	MetadataURL           string
+	IdentityEndpoint      string
+	IdentityHeader        string
+	ClientID              string
+	Resource              string
+	Validity              time.Duration
+	Quota                 int
+	Audience              string
+	RestrictedNamespace   string
+	RestrictedNetworks    []string
+	RestrictedPermissions []string
+	Logger                *zap.Logger
+	TracerProvider        trace.TracerProvider
+	Metrics               telemetry.Metrics
+	Cache                 common.TokenCache
+}
+
+// NewConfig returns new Config
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// SetAPI sets attribute and returns self
+func (t *Config) SetAPI(api string) *Config {
+	t.API = api
+	return t
+}
+
+// SetHTTPClient sets entity and returns self
+func (t *Config) SetHTTPClient(httpClient *http.Client) *Config {
+	t.HTTPClient = httpClient
+	return t
+}
+
+// GetHTTPClient returns entity. If entity is nil entity will be initialized and returned.
+func (t *Config) GetHTTPClient() *http.Client {
+
+	if t.HTTPClient == nil {
+		t.HTTPClient = &http.Client{}
+		t.GetLogger().Debug("HTTPClient created new")
+	} else {
+		t.GetLogger().Debug("HTTPClient set from config")
+	}
+
+	return t.HTTPClient
+}
+
+// SetRetryPolicy sets entity and returns self
+func (t *Config) SetRetryPolicy(retryPolicy *retry.Policy) *Config {
+	t.RetryPolicy = retryPolicy
+	return t
+}
+
+// GetRetryPolicy returns entity. If entity is nil retry.NewPolicy() is returned. The policy logs to
+// the logger of the config unless it has its own.
+func (t *Config) GetRetryPolicy() *retry.Policy {
+
+	if t.RetryPolicy == nil {
+		return retry.NewPolicy().SetLogger(t.GetLogger())
+	}
+
+	return t.RetryPolicy.WithLogger(t.GetLogger())
+}
+
+// SetMetadataURL sets the base URL of the Instance Metadata Service and returns self. If not set
+// DefaultMetadataURL is used.
+func (t *Config) SetMetadataURL(metadataURL string) *Config {
+	t.MetadataURL = metadataURL
+	return t
+}
+
+// GetMetadataURL returns attribute without a trailing slash. If not set DefaultMetadataURL is
+// returned.
+func (t *Config) GetMetadataURL() string {
+
+	if t.MetadataURL == "" {
+		return DefaultMetadataURL
+	}
+
+	return strings.TrimSuffix(t.MetadataURL, "/")
+}
+
+// SetIdentityEndpoint sets the App Service or Functions identity endpoint and its secret header
+// and returns self. If not set they are read from IDENTITY_ENDPOINT and IDENTITY_HEADER.
+func (t *Config) SetIdentityEndpoint(identityEndpoint, identityHeader string) *Config {
+	t.IdentityEndpoint = identityEndpoint
+	t.IdentityHeader = identityHeader
+	return t
+}
+
+// GetIdentityEndpoint returns the identity endpoint and its secret header. If not set they are
+// read from the environment. An empty endpoint means the Instance Metadata Service is used.
+func (t *Config) GetIdentityEndpoint() (string, string) {
+
+	if t.IdentityEndpoint == "" {
+		return os.Getenv(IdentityEndpointEnv), os.Getenv(IdentityHeaderEnv)
+	}
+
+	return t.IdentityEndpoint, t.IdentityHeader
+}
+
+// SetClientID sets the client ID of a user-assigned managed identity and returns self. If not set
+// the system-assigned identity is used.
+func (t *Config) SetClientID(clientID string) *Config {
+	t.ClientID = clientID
+	return t
+}
+
+// SetResource sets the resource the identity token is requested for and returns self. If not set
+// DefaultResource is used.
+func (t *Config) SetResource(resource string) *Config {
+	t.Resource = resource
+	return t
+}
+
+// GetResource returns attribute. If not set DefaultResource is returned.
+func (t *Config) GetResource() string {
+
+	if t.Resource == "" {
+		return DefaultResource
+	}
+
+	return t.Resource
+}
+
+// SetValidity sets the requested lifetime of the token and returns self. If not set
+// common.DefaultValidity is used.
+func (t *Config) SetValidity(validity time.Duration) *Config {
+	t.Validity = validity
+	return t
+}
+
+// SetQuota sets the number of times the token may be used and returns self. If not set the
+// token may be used without limit.
+func (t *Config) SetQuota(quota int) *Config {
+	t.Quota = quota
+	return t
+}
+
+// SetAudience sets the audience of the token and returns self
+func (t *Config) SetAudience(audience string) *Config {
+	t.Audience = audience
+	return t
+}
+
+// SetRestrictedNamespace restricts the token to the namespace and its children and returns self
+func (t *Config) SetRestrictedNamespace(restrictedNamespace string) *Config {
+	t.RestrictedNamespace = restrictedNamespace
+	return t
+}
+
+// SetRestrictedNetworks restricts the token to requests from the networks (CIDRs) and returns self
+func (t *Config) SetRestrictedNetworks(restrictedNetworks ...string) *Config {
+	t.RestrictedNetworks = restrictedNetworks
+	return t
+}
+
+// SetRestrictedPermissions restricts the token to the permissions (for example
+// "namespaces:get") and returns self
+func (t *Config) SetRestrictedPermissions(restrictedPermissions ...string) *Config {
+	t.RestrictedPermissions = restrictedPermissions
+	return t
+}
+
+// GetIssueOptions returns the parameters of the /issue request
+func (t *Config) GetIssueOptions() common.IssueOptions {
+
+	validity := t.Validity
+	if validity == 0 {
+		validity = common.DefaultValidity
+	}
+
+	return common.IssueOptions{
+		Validity:              validity.String(),
+		Quota:                 t.Quota,
+		Audience:              t.Audience,
+		RestrictedNamespace:   t.RestrictedNamespace,
+		RestrictedNetworks:    t.RestrictedNetworks,
+		RestrictedPermissions: t.RestrictedPermissions,
+	}
+}
+
+// SetLogger sets entity and returns self. If not set nothing is logged.
+func (t *Config) SetLogger(logger *zap.Logger) *Config {
+	t.Logger = logger
+	return t
+}
+
+// GetLogger returns entity. If entity is nil a no-op logger is returned.
+func (t *Config) GetLogger() *zap.Logger {
+
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+
+	return t.Logger
+}
+
+// SetTracerProvider sets interface and returns self. Every /issue exchange is wrapped in a span.
+// If not set nothing is traced.
+func (t *Config) SetTracerProvider(tracerProvider trace.TracerProvider) *Config {
+	t.TracerProvider = tracerProvider
+	return t
+}
+
+// SetMetrics sets interface and returns self. If not set nothing is measured.
+func (t *Config) SetMetrics(metrics telemetry.Metrics) *Config {
+	t.Metrics = metrics
+	return t
+}
+
+// SetCache sets interface and returns self. Issued tokens are stored in the cache and reused
+// while they are valid, also by other processes. If not set tokens are only kept in memory.
+func (t *Config) SetCache(cache common.TokenCache) *Config {
+	t.Cache = cache
+	return t
+}
+
+// Build returns entity
+func (t *Config) Build() (*Client, error) {
+	return NewClient(t)
+}
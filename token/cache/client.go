@@ -0,0 +1,288 @@
+package token
+
+/*
+
+This implements common.TokenCache with one file per entry in a directory, so that short-lived
+processes can reuse the tokens issued to the processes before them instead of calling the
+metadata server and /issue on every start.
+
+	cache, err := token_cache.NewConfig().Build()
+
+	tokenProvider, err := token_gcp.NewConfig().
+		SetAPI(api).
+		SetCache(cache).
+		Build()
+
+The directory is only accessible by the owner and the entries are written with mode 0600. If an
+EncryptionKey is set the entries are encrypted with AES-GCM. Entries are replaced atomically and
+Lock takes a file lock so that several processes can share the cache. A cached token is reused
+until Margin before it expires or until a provider evicts it with Delete when it is invalidated.
+
+*/
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+// Client the token cache. It is safe for concurrent use, also by several processes.
+type Client struct {
+	dir    string
+	aead   cipher.AEAD
+	margin time.Duration
+	logger *zap.Logger
+}
+
+// NewClient returns new Client and creates the directory of the cache if it does not exist
+func NewClient(config *Config) (*Client, error) {
+
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
+
+	var errors *multierror.Error
+
+	dir, err := config.GetDir()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	if config.Margin < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Margin must not be negative"))
+	}
+
+	var aead cipher.AEAD
+
+	if config.EncryptionKey != nil {
+		block, err := aes.NewCipher(config.EncryptionKey)
+		if err != nil {
+			errors = multierror.Append(errors, fmt.Errorf("attribute EncryptionKey is invalid: %w", err))
+		} else {
+			aead, _ = cipher.NewGCM(block)
+		}
+	}
+
+	err = errors.ErrorOrNil()
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	// MkdirAll leaves the mode of an existing directory as it is
+	info, err := os.Stat(dir)
+	if err == nil && info.Mode().Perm()&0077 != 0 {
+		logger.Debug("restricting the mode of the token cache directory", zap.String("dir", dir))
+		err = os.Chmod(dir, 0700)
+	}
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	logger.Debug("returning NewClient")
+	return &Client{
+		dir:    dir,
+		aead:   aead,
+		margin: config.GetMargin(),
+		logger: logger,
+	}, nil
+}
+
+// Lock locks the entry of key until the returned function is called. It waits for the lock until
+// ctx is done. The lock file is removed when the lock is released.
+func (t *Client) Lock(ctx context.Context, key string) (func(), error) {
+
+	path := t.path(key) + ".lock"
+
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+		if err != nil {
+			return nil, err
+		}
+
+		err = lockFile(ctx, file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		// The previous holder may have removed the lock file after this one opened it, in which
+		// case the lock is on a file that no other process can see
+		if sameFile(file, path) {
+			return func() {
+				os.Remove(path)
+				file.Close()
+			}, nil
+		}
+
+		file.Close()
+	}
+}
+
+// Get returns the token cached for key or nil if there is none that is valid for longer than
+// Margin. Entries that cannot be read are ignored.
+func (t *Client) Get(key string) *common.PrismaToken {
+
+	data, err := ioutil.ReadFile(t.path(key))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			t.logger.Debug("token cache entry not readable", zap.String("key", key), zap.Error(err))
+		}
+		return nil
+	}
+
+	data, err = t.open(key, data)
+	if err != nil {
+		t.logger.Debug("token cache entry not readable", zap.String("key", key), zap.Error(err))
+		return nil
+	}
+
+	var token *common.PrismaToken
+	err = json.Unmarshal(data, &token)
+	if err != nil || token == nil {
+		t.logger.Debug("token cache entry not readable", zap.String("key", key), zap.Error(err))
+		return nil
+	}
+
+	if time.Now().Add(t.margin).Unix() > token.Claims.Exp {
+		t.logger.Debug("token cache entry expires within the margin", zap.String("key", key))
+		return nil
+	}
+
+	t.logger.Debug("token cache hit", zap.String("key", key))
+	return token
+}
+
+// Put caches the token for key. The entry is replaced atomically.
+func (t *Client) Put(key string, token *common.PrismaToken) error {
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	data, err = t.seal(key, data)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(t.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	// TempFile creates the file with mode 0600 but the umask is not to be relied on
+	err = file.Chmod(0600)
+	if err == nil {
+		_, err = file.Write(data)
+	}
+
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), t.path(key))
+	}
+
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	t.logger.Debug("token cached", zap.String("key", key))
+	return nil
+}
+
+// Delete evicts the token cached for key. It is not an error if there is none. It waits for the
+// lock of the entry until ctx is done so that a token being issued is not cached after it.
+func (t *Client) Delete(ctx context.Context, key string) error {
+
+	unlock, err := t.Lock(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = os.Remove(t.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	t.logger.Debug("token cache entry deleted", zap.String("key", key))
+	return nil
+}
+
+func (t *Client) path(key string) string {
+	return filepath.Join(t.dir, key)
+}
+
+// sameFile returns true if file is still the one at path
+func sameFile(file *os.File, path string) bool {
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(fileInfo, pathInfo)
+}
+
+// seal encrypts data if an EncryptionKey is set. The key of the entry is authenticated so that an
+// entry cannot be moved to another key.
+func (t *Client) seal(key string, data []byte) ([]byte, error) {
+
+	if t.aead == nil {
+		return data, nil
+	}
+
+	nonce := make([]byte, t.aead.NonceSize())
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.aead.Seal(nonce, nonce, data, []byte(key)), nil
+}
+
+// open decrypts data if an EncryptionKey is set
+func (t *Client) open(key string, data []byte) ([]byte, error) {
+
+	if t.aead == nil {
+		return data, nil
+	}
+
+	if len(data) < t.aead.NonceSize() {
+		return nil, fmt.Errorf("entry is too short")
+	}
+
+	nonce := data[:t.aead.NonceSize()]
+
+	return t.aead.Open(nil, nonce, data[t.aead.NonceSize():], []byte(key))
+}
@@ -0,0 +1,110 @@
+package token_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	token_cache "github.com/aporeto-se/prisma-sdk-go-v2/token/cache"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+func newToken(token string, validity time.Duration) *common.PrismaToken {
+	result := &common.PrismaToken{Token: token}
+	result.Claims.Exp = time.Now().Add(validity).Unix()
+	return result
+}
+
+func TestPutGetDelete(t *testing.T) {
+
+	cache, err := token_cache.NewConfig().
+		SetDir(t.TempDir()).
+		SetEncryptionKey(make([]byte, 32)).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	key := common.CacheKey("https://api.example.com", "AWSSecurityToken", "AKIAEXAMPLE", common.IssueOptions{})
+
+	if token := cache.Get(key); token != nil {
+		t.Fatalf("Get on an empty cache returned %q", token.Token)
+	}
+
+	err = cache.Put(key, newToken("token", time.Hour))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if token := cache.Get(key); token == nil || token.Token != "token" {
+		t.Fatalf("Get did not return the cached token")
+	}
+
+	err = cache.Delete(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if token := cache.Get(key); token != nil {
+		t.Errorf("Get returned the deleted token")
+	}
+
+	// Deleting a missing entry is not an error
+	err = cache.Delete(context.Background(), key)
+	if err != nil {
+		t.Errorf("Delete of a missing entry: %v", err)
+	}
+}
+
+func TestGetWithinMargin(t *testing.T) {
+
+	cache, err := token_cache.NewConfig().
+		SetDir(t.TempDir()).
+		SetMargin(time.Minute).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	key := common.CacheKey("https://api.example.com", "AWSSecurityToken", "AKIAEXAMPLE", common.IssueOptions{})
+
+	err = cache.Put(key, newToken("token", 30*time.Second))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if token := cache.Get(key); token != nil {
+		t.Errorf("Get returned a token that expires within the margin")
+	}
+}
+
+func TestNewClientRestrictsDirMode(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on windows")
+	}
+
+	dir := filepath.Join(t.TempDir(), "cache")
+
+	err := os.Mkdir(dir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = token_cache.NewConfig().SetDir(dir).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("mode of the existing cache directory is %v, want 0700", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,94 @@
+package token
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultMargin is the default time before expiry after which a cached token is no longer reused
+const DefaultMargin = 5 * time.Minute
+
+// Config config
+type Config struct {
+	Dir           string
+	EncryptionKey []byte
+	Margin        time.Duration
+	Logger        *zap.Logger
+}
+
+// NewConfig returns new Config
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// SetDir sets the directory of the cache and returns self. If not set prisma-sdk-go/tokens in the
+// user cache directory is used.
+func (t *Config) SetDir(dir string) *Config {
+	t.Dir = dir
+	return t
+}
+
+// GetDir returns attribute. If not set prisma-sdk-go/tokens in the user cache directory is
+// returned.
+func (t *Config) GetDir() (string, error) {
+
+	if t.Dir != "" {
+		return t.Dir, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "prisma-sdk-go", "tokens"), nil
+}
+
+// SetEncryptionKey sets the AES key (16, 24 or 32 bytes) the cached tokens are encrypted with and
+// returns self. If not set the tokens are stored in clear and only protected by the permissions of
+// the files.
+func (t *Config) SetEncryptionKey(encryptionKey []byte) *Config {
+	t.EncryptionKey = encryptionKey
+	return t
+}
+
+// SetMargin sets the time before expiry after which a cached token is no longer reused and
+// returns self. If not set DefaultMargin is used.
+func (t *Config) SetMargin(margin time.Duration) *Config {
+	t.Margin = margin
+	return t
+}
+
+// GetMargin returns attribute. If not set DefaultMargin is returned.
+func (t *Config) GetMargin() time.Duration {
+
+	if t.Margin == 0 {
+		return DefaultMargin
+	}
+
+	return t.Margin
+}
+
+// SetLogger sets entity and returns self. If not set nothing is logged.
+func (t *Config) SetLogger(logger *zap.Logger) *Config {
+	t.Logger = logger
+	return t
+}
+
+// GetLogger returns entity. If entity is nil a no-op logger is returned.
+func (t *Config) GetLogger() *zap.Logger {
+
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+
+	return t.Logger
+}
+
+// Build returns entity
+func (t *Config) Build() (*Client, error) {
+	return NewClient(t)
+}
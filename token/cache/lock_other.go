@@ -0,0 +1,15 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package token
+
+import (
+	"context"
+	"os"
+)
+
+// lockFile does nothing on this platform. Entries are still written atomically so concurrent
+// processes never read a partial entry, but they may issue a token for the same key at once.
+func lockFile(ctx context.Context, file *os.File) error {
+	return ctx.Err()
+}
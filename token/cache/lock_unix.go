@@ -0,0 +1,35 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package token
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockPollInterval is how long lockFile waits before it tries again to take a lock that is held
+const lockPollInterval = 10 * time.Millisecond
+
+// lockFile takes an exclusive advisory lock on the file. It polls for the lock rather than block
+// in flock so that it gives up when ctx is done. The lock is released when the file is closed.
+func lockFile(ctx context.Context, file *os.File) error {
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err != syscall.EWOULDBLOCK && err != syscall.EINTR {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
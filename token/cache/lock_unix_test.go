@@ -0,0 +1,109 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package token_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	token_cache "github.com/aporeto-se/prisma-sdk-go-v2/token/cache"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+func TestLockHonorsContext(t *testing.T) {
+
+	dir := t.TempDir()
+
+	cache, err := token_cache.NewConfig().SetDir(dir).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	key := common.CacheKey("https://api.example.com", "Certificate", "certificate", common.IssueOptions{})
+
+	unlock, err := cache.Lock(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+
+	// The lock is held by another open file, as it would be by another process
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = cache.Lock(ctx, key)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Lock of a held entry returned %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	err = cache.Delete(ctx, key)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Delete of a held entry returned %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	unlock()
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	unlock, err = cache.Lock(ctx, key)
+	if err != nil {
+		t.Fatalf("Lock after unlock: %v", err)
+	}
+	unlock()
+
+	// The lock files are removed when the locks are released
+	matches, err := filepath.Glob(filepath.Join(dir, "*.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 0 {
+		t.Errorf("lock files left behind: %v", matches)
+	}
+}
+
+func TestLockWaitsForHolder(t *testing.T) {
+
+	cache, err := token_cache.NewConfig().SetDir(t.TempDir()).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	key := common.CacheKey("https://api.example.com", "Certificate", "certificate", common.IssueOptions{})
+
+	unlock, err := cache.Lock(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+
+	locked := make(chan error)
+	go func() {
+		unlock, err := cache.Lock(context.Background(), key)
+		if err == nil {
+			unlock()
+		}
+		locked <- err
+	}()
+
+	select {
+	case err = <-locked:
+		t.Fatalf("Lock of a held entry returned %v before it was released", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Releasing removes the lock file the waiter opened, so it has to lock a new one
+	unlock()
+
+	select {
+	case err = <-locked:
+		if err != nil {
+			t.Errorf("Lock after release: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Lock did not return after the entry was released")
+	}
+}
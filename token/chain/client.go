@@ -0,0 +1,245 @@
+package token
+
+/*
+
+This implements the TokenProvider Interface by trying an ordered list of candidate providers.
+The first candidate that is detected and returns a token is selected and used from then on.
+Candidates that are skipped are reported with the reason by Attempts.
+
+If no candidates are configured the environment is auto-detected in this order
+
+env: a token in PRISMA_TOKEN, APOCTL_TOKEN or ENFORCERD_TOKEN
+aws-envvars: AWS credentials in AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+gcp: the GCP metadata server answers within the probe timeout
+azure: IDENTITY_ENDPOINT is set or the Azure metadata server answers within the probe timeout
+aws-meta: the AWS metadata server answers within the probe timeout
+
+*/
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+// TokenProvider is a provider tried by the chain. It has the same methods as the TokenProvider of
+// the api package.
+type TokenProvider interface {
+	Token(context.Context) (string, error)
+	AccountID(ctx context.Context) (string, error)
+}
+
+// Invalidator is optionally implemented by a TokenProvider; see the api package
+type Invalidator interface {
+	Invalidate()
+}
+
+// Refresher is optionally implemented by a TokenProvider; see the refresh package
+type Refresher interface {
+	Refresh(context.Context) (string, error)
+}
+
+// Candidate is a provider tried by the chain. Detect returns the provider or an error explaining
+// why the candidate is skipped.
+type Candidate struct {
+	Name   string
+	Detect func(ctx context.Context) (TokenProvider, error)
+}
+
+// Attempt is a candidate that was tried. Err is nil for the selected candidate.
+type Attempt struct {
+	Name string
+	Err  error
+}
+
+// Client the token client. It is safe for concurrent use; the candidates are tried by one caller
+// at a time and the other callers wait for the result until their context is done.
+type Client struct {
+	candidates []*Candidate
+	logger     *zap.Logger
+
+	mutex         common.Mutex
+	tokenProvider TokenProvider
+	selected      string
+	attempts      []*Attempt
+}
+
+// NewClient returns new Client. The candidates are not tried until the first call to Token or
+// AccountID.
+func NewClient(config *Config) (*Client, error) {
+
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
+
+	candidates := config.Candidates
+	if len(candidates) == 0 {
+		candidates = DefaultCandidates(config)
+	}
+
+	for _, v := range candidates {
+		if v.Name == "" || v.Detect == nil {
+			logger.Debug("returning NewClient with error(s)")
+			return nil, fmt.Errorf("attributes Name and Detect are required for every candidate")
+		}
+	}
+
+	logger.Debug("returning NewClient")
+	return &Client{
+		candidates: candidates,
+		logger:     logger,
+	}, nil
+}
+
+// Token returns token string or error. On the first call the candidates are tried in order.
+func (t *Client) Token(ctx context.Context) (string, error) {
+
+	err := t.mutex.LockContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tokenProvider := t.tokenProvider
+	if tokenProvider == nil {
+		defer t.mutex.Unlock()
+		return t.selectProvider(ctx)
+	}
+
+	t.mutex.Unlock()
+
+	return tokenProvider.Token(ctx)
+}
+
+// AccountID returns Cloud Account ID or error
+func (t *Client) AccountID(ctx context.Context) (string, error) {
+
+	err := t.mutex.LockContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if t.tokenProvider == nil {
+		_, err = t.selectProvider(ctx)
+		if err != nil {
+			t.mutex.Unlock()
+			return "", err
+		}
+	}
+
+	tokenProvider := t.tokenProvider
+	t.mutex.Unlock()
+
+	return tokenProvider.AccountID(ctx)
+}
+
+// Claims returns the claims of the token of the selected provider
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
+
+	token, err := t.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.ParseToken(token)
+}
+
+// Invalidate invalidates the token of the selected provider if it implements Invalidator
+func (t *Client) Invalidate() {
+
+	t.mutex.Lock()
+	tokenProvider := t.tokenProvider
+	t.mutex.Unlock()
+
+	if invalidator, ok := tokenProvider.(Invalidator); ok {
+		invalidator.Invalidate()
+	}
+}
+
+// Refresh returns a new token from the selected provider if it implements Refresher and its
+// current token otherwise. On the first call the candidates are tried in order.
+func (t *Client) Refresh(ctx context.Context) (string, error) {
+
+	err := t.mutex.LockContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tokenProvider := t.tokenProvider
+	if tokenProvider == nil {
+		defer t.mutex.Unlock()
+		return t.selectProvider(ctx)
+	}
+
+	t.mutex.Unlock()
+
+	if refresher, ok := tokenProvider.(Refresher); ok {
+		return refresher.Refresh(ctx)
+	}
+
+	return tokenProvider.Token(ctx)
+}
+
+// Selected returns the name of the selected candidate or an empty string if none is selected yet
+func (t *Client) Selected() string {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.selected
+}
+
+// Attempts returns the candidates tried so far in order and why they were skipped
+func (t *Client) Attempts() []*Attempt {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var result []*Attempt
+	return append(result, t.attempts...)
+}
+
+// selectProvider tries the candidates in order and returns the token of the first one that
+// succeeds. It must be called with the mutex held.
+func (t *Client) selectProvider(ctx context.Context) (string, error) {
+
+	t.attempts = nil
+
+	for _, candidate := range t.candidates {
+
+		tokenProvider, err := candidate.Detect(ctx)
+		if err != nil {
+			t.skip(candidate.Name, err)
+			continue
+		}
+
+		token, err := tokenProvider.Token(ctx)
+		if err != nil {
+			t.skip(candidate.Name, err)
+			continue
+		}
+
+		t.logger.Info("token provider selected", zap.String("provider", candidate.Name))
+
+		t.attempts = append(t.attempts, &Attempt{Name: candidate.Name})
+		t.tokenProvider = tokenProvider
+		t.selected = candidate.Name
+
+		return token, nil
+	}
+
+	var reasons []string
+	for _, v := range t.attempts {
+		reasons = append(reasons, v.Name+": "+v.Err.Error())
+	}
+
+	return "", fmt.Errorf("no token provider available (%s)", strings.Join(reasons, "; "))
+}
+
+func (t *Client) skip(name string, err error) {
+	t.logger.Debug("token provider skipped", zap.String("provider", name), zap.Error(err))
+	t.attempts = append(t.attempts, &Attempt{Name: name, Err: err})
+}
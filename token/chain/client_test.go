@@ -0,0 +1,95 @@
+package token_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_chain "github.com/aporeto-se/prisma-sdk-go-v2/token/chain"
+)
+
+func TestSelectsFirstDetected(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client, err := token_chain.NewConfig().
+		AddCandidate(&token_chain.Candidate{
+			Name: "missing",
+			Detect: func(context.Context) (token_chain.TokenProvider, error) {
+				return nil, fmt.Errorf("not detected")
+			},
+		}).
+		AddProvider("prismatest", server.TokenProvider()).
+		AddProvider("unused", server.TokenProvider()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	if _, err := client.Token(context.Background()); err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	if client.Selected() != "prismatest" {
+		t.Errorf("Selected is %q, want prismatest", client.Selected())
+	}
+
+	attempts := client.Attempts()
+	if len(attempts) != 2 || attempts[0].Err == nil || attempts[1].Err != nil {
+		t.Errorf("unexpected attempts %+v", attempts)
+	}
+}
+
+func TestConcurrentSelection(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	var detected int32
+
+	client, err := token_chain.NewConfig().
+		AddCandidate(&token_chain.Candidate{
+			Name: "prismatest",
+			Detect: func(context.Context) (token_chain.TokenProvider, error) {
+				atomic.AddInt32(&detected, 1)
+				return server.TokenProvider(), nil
+			},
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var err error
+			switch i % 3 {
+			case 0:
+				_, err = client.Token(context.Background())
+			case 1:
+				_, err = client.AccountID(context.Background())
+			default:
+				client.Invalidate()
+			}
+
+			if err != nil {
+				t.Errorf("call %d: %v", i, err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&detected); n != 1 {
+		t.Errorf("candidate was detected %d times, want 1", n)
+	}
+}
@@ -0,0 +1,127 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+// DefaultProbeTimeout is the default timeout of the metadata server probes
+const DefaultProbeTimeout = time.Second
+
+// Config config
+type Config struct {
+	API            string
+	HTTPClient     *http.Client
+	RetryPolicy    *retry.Policy
+	Logger         *zap.Logger
+	TracerProvider trace.TracerProvider
+	Metrics        telemetry.Metrics
+	Cache          common.TokenCache
+	ProbeTimeout   time.Duration
+	Candidates     []*Candidate
+}
+
+// NewConfig returns new Config
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// SetAPI sets attribute and returns self. It is required by the detected providers except env.
+func (t *Config) SetAPI(api string) *Config {
+	t.API = api
+	return t
+}
+
+// SetHTTPClient sets entity and returns self. It is passed to the detected providers.
+func (t *Config) SetHTTPClient(httpClient *http.Client) *Config {
+	t.HTTPClient = httpClient
+	return t
+}
+
+// SetRetryPolicy sets entity and returns self. It is passed to the detected providers.
+func (t *Config) SetRetryPolicy(retryPolicy *retry.Policy) *Config {
+	t.RetryPolicy = retryPolicy
+	return t
+}
+
+// SetLogger sets entity and returns self. If not set nothing is logged.
+func (t *Config) SetLogger(logger *zap.Logger) *Config {
+	t.Logger = logger
+	return t
+}
+
+// GetLogger returns entity. If entity is nil a no-op logger is returned.
+func (t *Config) GetLogger() *zap.Logger {
+
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+
+	return t.Logger
+}
+
+// SetTracerProvider sets interface and returns self. It is passed to the detected providers.
+func (t *Config) SetTracerProvider(tracerProvider trace.TracerProvider) *Config {
+	t.TracerProvider = tracerProvider
+	return t
+}
+
+// SetMetrics sets interface and returns self. It is passed to the detected providers.
+func (t *Config) SetMetrics(metrics telemetry.Metrics) *Config {
+	t.Metrics = metrics
+	return t
+}
+
+// SetCache sets interface and returns self. It is passed to the detected providers that issue
+// tokens.
+func (t *Config) SetCache(cache common.TokenCache) *Config {
+	t.Cache = cache
+	return t
+}
+
+// SetProbeTimeout sets the timeout of the metadata server probes and returns self. If not set
+// DefaultProbeTimeout is used.
+func (t *Config) SetProbeTimeout(probeTimeout time.Duration) *Config {
+	t.ProbeTimeout = probeTimeout
+	return t
+}
+
+// GetProbeTimeout returns attribute. If not set DefaultProbeTimeout is returned.
+func (t *Config) GetProbeTimeout() time.Duration {
+
+	if t.ProbeTimeout <= 0 {
+		return DefaultProbeTimeout
+	}
+
+	return t.ProbeTimeout
+}
+
+// AddCandidate appends candidates to the chain and returns self. If no candidates are added the
+// environment is auto-detected with DefaultCandidates.
+func (t *Config) AddCandidate(candidates ...*Candidate) *Config {
+	t.Candidates = append(t.Candidates, candidates...)
+	return t
+}
+
+// AddProvider appends an already built provider to the chain and returns self
+func (t *Config) AddProvider(name string, tokenProvider TokenProvider) *Config {
+	return t.AddCandidate(&Candidate{
+		Name: name,
+		Detect: func(context.Context) (TokenProvider, error) {
+			return tokenProvider, nil
+		},
+	})
+}
+
+// Build returns entity
+func (t *Config) Build() (*Client, error) {
+	return NewClient(t)
+}
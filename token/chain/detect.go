@@ -0,0 +1,200 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	aws_envvars "github.com/aporeto-se/prisma-sdk-go-v2/token/aws/envvars"
+	aws_meta "github.com/aporeto-se/prisma-sdk-go-v2/token/aws/meta"
+	token_azure "github.com/aporeto-se/prisma-sdk-go-v2/token/azure"
+	token_env "github.com/aporeto-se/prisma-sdk-go-v2/token/env"
+	token_gcp "github.com/aporeto-se/prisma-sdk-go-v2/token/gcp"
+)
+
+const (
+
+	// NameEnv is the name of the env candidate
+	NameEnv = "env"
+
+	// NameAWSEnvVars is the name of the aws-envvars candidate
+	NameAWSEnvVars = "aws-envvars"
+
+	// NameGCP is the name of the gcp candidate
+	NameGCP = "gcp"
+
+	// NameAzure is the name of the azure candidate
+	NameAzure = "azure"
+
+	// NameAWSMeta is the name of the aws-meta candidate
+	NameAWSMeta = "aws-meta"
+
+	// AccessKeyIDEnv enviroment variable
+	AccessKeyIDEnv = "AWS_ACCESS_KEY_ID"
+
+	// SecretAccessKeyEnv enviroment variable
+	SecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+
+	// SessionTokenEnv enviroment variable
+	SessionTokenEnv = "AWS_SESSION_TOKEN"
+
+	// GCEMetadataHostEnv enviroment variable overriding the host of the GCP metadata server
+	GCEMetadataHostEnv = "GCE_METADATA_HOST"
+
+	gcpMetadataHost       = "169.254.169.254"
+	awsMetadataTokenURL   = "http://169.254.169.254/latest/api/token"
+	azureMetadataProbeURL = token_azure.DefaultMetadataURL + "/metadata/instance?api-version=2021-02-01"
+)
+
+// DefaultCandidates returns the candidates used when none are configured: env, aws-envvars, gcp,
+// azure and aws-meta. The providers are built from the API, HTTPClient, RetryPolicy, Logger,
+// TracerProvider, Metrics and Cache of config.
+func DefaultCandidates(config *Config) []*Candidate {
+	return []*Candidate{
+		{Name: NameEnv, Detect: config.detectEnv},
+		{Name: NameAWSEnvVars, Detect: config.detectAWSEnvVars},
+		{Name: NameGCP, Detect: config.detectGCP},
+		{Name: NameAzure, Detect: config.detectAzure},
+		{Name: NameAWSMeta, Detect: config.detectAWSMeta},
+	}
+}
+
+func (t *Config) detectEnv(ctx context.Context) (TokenProvider, error) {
+
+	if os.Getenv(token_env.PrismaTokenEnv) == "" && os.Getenv(token_env.ApoctlTokenEnv) == "" && os.Getenv(token_env.EnforcerdTokenEnv) == "" {
+		return nil, fmt.Errorf("none of %s, %s or %s is set", token_env.PrismaTokenEnv, token_env.ApoctlTokenEnv, token_env.EnforcerdTokenEnv)
+	}
+
+	return token_env.NewConfig().
+		SetLogger(t.GetLogger()).
+		Build()
+}
+
+func (t *Config) detectAWSEnvVars(ctx context.Context) (TokenProvider, error) {
+
+	accessKeyID := os.Getenv(AccessKeyIDEnv)
+	secretAccessKey := os.Getenv(SecretAccessKeyEnv)
+	sessionToken := os.Getenv(SessionTokenEnv)
+
+	if accessKeyID == "" || secretAccessKey == "" || sessionToken == "" {
+		return nil, fmt.Errorf("%s, %s and %s are not all set", AccessKeyIDEnv, SecretAccessKeyEnv, SessionTokenEnv)
+	}
+
+	return aws_envvars.NewConfig().
+		SetAPI(t.API).
+		SetAccessKeyID(accessKeyID).
+		SetSecretAccessKey(secretAccessKey).
+		SetSessionToken(sessionToken).
+		SetHTTPClient(t.HTTPClient).
+		SetRetryPolicy(t.RetryPolicy).
+		SetLogger(t.GetLogger()).
+		SetTracerProvider(t.TracerProvider).
+		SetMetrics(t.Metrics).
+		SetCache(t.Cache).
+		Build()
+}
+
+func (t *Config) detectGCP(ctx context.Context) (TokenProvider, error) {
+
+	host := os.Getenv(GCEMetadataHostEnv)
+	if host == "" {
+		host = gcpMetadataHost
+	}
+
+	resp, err := t.probe(ctx, "GET", "http://"+host+"/computeMetadata/v1/", "Metadata-Flavor", "Google")
+	if err != nil {
+		return nil, fmt.Errorf("GCP metadata server not reachable: %w", err)
+	}
+
+	if resp.Header.Get("Metadata-Flavor") != "Google" {
+		return nil, fmt.Errorf("GCP metadata server not found")
+	}
+
+	return token_gcp.NewConfig().
+		SetAPI(t.API).
+		SetHTTPClient(t.HTTPClient).
+		SetRetryPolicy(t.RetryPolicy).
+		SetLogger(t.GetLogger()).
+		SetTracerProvider(t.TracerProvider).
+		SetMetrics(t.Metrics).
+		SetCache(t.Cache).
+		Build()
+}
+
+func (t *Config) detectAzure(ctx context.Context) (TokenProvider, error) {
+
+	// App Service and Functions have no Instance Metadata Service
+	if os.Getenv(token_azure.IdentityEndpointEnv) == "" {
+
+		resp, err := t.probe(ctx, "GET", azureMetadataProbeURL, "Metadata", "true")
+		if err != nil {
+			return nil, fmt.Errorf("Azure metadata server not reachable: %w", err)
+		}
+
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("Azure metadata server returned status code %d", resp.StatusCode)
+		}
+	}
+
+	return token_azure.NewConfig().
+		SetAPI(t.API).
+		SetHTTPClient(t.HTTPClient).
+		SetRetryPolicy(t.RetryPolicy).
+		SetLogger(t.GetLogger()).
+		SetTracerProvider(t.TracerProvider).
+		SetMetrics(t.Metrics).
+		SetCache(t.Cache).
+		Build()
+}
+
+func (t *Config) detectAWSMeta(ctx context.Context) (TokenProvider, error) {
+
+	resp, err := t.probe(ctx, "PUT", awsMetadataTokenURL, "X-aws-ec2-metadata-token-ttl-seconds", "60")
+	if err != nil {
+		return nil, fmt.Errorf("AWS metadata server not reachable: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("AWS metadata server returned status code %d", resp.StatusCode)
+	}
+
+	return aws_meta.NewConfig().
+		SetAPI(t.API).
+		SetHTTPClient(t.HTTPClient).
+		SetRetryPolicy(t.RetryPolicy).
+		SetLogger(t.GetLogger()).
+		SetTracerProvider(t.TracerProvider).
+		SetMetrics(t.Metrics).
+		SetCache(t.Cache).
+		Build()
+}
+
+// probe sends a request that must be answered within the probe timeout. The body of the response
+// is discarded.
+func (t *Config) probe(ctx context.Context, method, url, header, value string) (*http.Response, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, t.GetProbeTimeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(header, value)
+
+	// The probes must not go through a proxy so the default client is not used
+	client := &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return resp, nil
+}
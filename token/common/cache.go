@@ -0,0 +1,45 @@
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// TokenCache stores issued tokens so that they can be reused across process restarts. The
+// providers that issue tokens use it when one is set. Implementations must be safe for use by
+// several processes at once.
+type TokenCache interface {
+
+	// Lock locks the entry of key until the returned function is called so that only one process
+	// issues a token for key at a time. It waits for the lock until ctx is done.
+	Lock(ctx context.Context, key string) (func(), error)
+
+	// Get returns the token cached for key or nil if there is none that is still valid
+	Get(key string) *PrismaToken
+
+	// Put caches the token for key
+	Put(key string, token *PrismaToken) error
+
+	// Delete evicts the token cached for key, such as a token that was rejected by the API. It
+	// takes the lock of the entry and waits for it until ctx is done.
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheKey returns the key of the tokens issued by api for the identity in realm with options.
+// The identity is whatever distinguishes the caller within the realm, such as an access key ID, a
+// role name or a service account.
+func CacheKey(api, realm, identity string, options IssueOptions) string {
+
+	data, _ := json.Marshal(struct {
+		API      string       `json:"api"`
+		Realm    string       `json:"realm"`
+		Identity string       `json:"identity"`
+		Options  IssueOptions `json:"options"`
+	}{api, realm, identity, options})
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
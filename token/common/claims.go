@@ -0,0 +1,119 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims are the claims of a token issued by Prisma. Data holds the claims of the realm, such as
+// the account of an AWS token or the project of a GCP token.
+type Claims struct {
+	Realm        string            `json:"realm,omitempty" yaml:"realm,omitempty"`
+	Data         map[string]string `json:"data,omitempty" yaml:"data,omitempty"`
+	Restrictions Restrictions      `json:"restrictions,omitempty" yaml:"restrictions,omitempty"`
+	Exp          int64             `json:"exp,omitempty" yaml:"exp,omitempty"`
+	Iat          int64             `json:"iat,omitempty" yaml:"iat,omitempty"`
+	Iss          string            `json:"iss,omitempty" yaml:"iss,omitempty"`
+	Sub          string            `json:"sub,omitempty" yaml:"sub,omitempty"`
+}
+
+// ParseToken returns the claims of a token. The signature is not verified; the claims are only
+// meant for inspection and must not be trusted for authorization.
+func ParseToken(token string) (*Claims, error) {
+
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("token payload is invalid: %w", err)
+	}
+
+	var claims *Claims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("token claims are invalid: %w", err)
+	}
+
+	if claims == nil {
+		return nil, fmt.Errorf("token has no claims")
+	}
+
+	return claims, nil
+}
+
+// Subject returns the sub claim
+func (t *Claims) Subject() string {
+	return t.Sub
+}
+
+// Issuer returns the iss claim
+func (t *Claims) Issuer() string {
+	return t.Iss
+}
+
+// Organization returns the organization of the data claims
+func (t *Claims) Organization() string {
+	return t.Data["organization"]
+}
+
+// IssuedAt returns the time the token was issued
+func (t *Claims) IssuedAt() time.Time {
+	return time.Unix(t.Iat, 0)
+}
+
+// ExpiresAt returns the time the token expires
+func (t *Claims) ExpiresAt() time.Time {
+	return time.Unix(t.Exp, 0)
+}
+
+// ExpiresIn returns the time left until the token expires. It is negative if the token has
+// expired.
+func (t *Claims) ExpiresIn() time.Duration {
+	return time.Until(t.ExpiresAt())
+}
+
+// IsExpired returns true if the token expires within skew. A skew allows for clock differences
+// between this host and the API.
+func (t *Claims) IsExpired(skew time.Duration) bool {
+	return t.ExpiresIn() <= skew
+}
+
+// Namespace returns the namespace the token is restricted to or an empty string if it is not
+// restricted
+func (t *Claims) Namespace() string {
+	return t.Restrictions.Namespace
+}
+
+// AccountID returns the cloud account of the token: the account (organization) for AWS, the
+// project number for GCP and the subscription for Azure. It returns an error for the other realms.
+func (t *Claims) AccountID() (string, error) {
+
+	var result string
+
+	switch t.Realm {
+
+	case "AWSSecurityToken":
+		result = t.Data["organization"]
+
+	case "GCPIdentityToken":
+		result = t.Data["projectnumber"]
+
+	case "AzureIdentityToken":
+		result = t.Data["subscriptionid"]
+
+	default:
+		return "", fmt.Errorf("realm %s has no cloud account", t.Realm)
+	}
+
+	if result == "" {
+		return "", fmt.Errorf("unable to get cloud account ID")
+	}
+
+	return result, nil
+}
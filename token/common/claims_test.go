@@ -0,0 +1,150 @@
+package common_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+// jwt returns an unsigned JWT with the payload encoded with encoding
+func jwt(encoding *base64.Encoding, payload string) string {
+	return "eyJhbGciOiJub25lIn0." + encoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func TestParseToken(t *testing.T) {
+
+	// The payload is chosen so that its padded encoding ends in "="
+	payload := `{"realm":"Certificate","sub":"ab"}`
+
+	tests := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{"unpadded", jwt(base64.RawURLEncoding, payload), ""},
+		{"padded", jwt(base64.URLEncoding, payload), ""},
+		{"surrounding whitespace", " " + jwt(base64.RawURLEncoding, payload) + "\n", ""},
+		{"empty", "", "token is not a JWT"},
+		{"two parts", "header.payload", "token is not a JWT"},
+		{"four parts", jwt(base64.RawURLEncoding, payload) + ".extra", "token is not a JWT"},
+		{"payload not base64url", "header.pay+load/.signature", "token payload is invalid"},
+		{"payload not JSON", jwt(base64.RawURLEncoding, "claims"), "token claims are invalid"},
+		{"payload null", jwt(base64.RawURLEncoding, "null"), "token has no claims"},
+	}
+
+	if !strings.HasSuffix(base64.URLEncoding.EncodeToString([]byte(payload)), "=") {
+		t.Fatal("payload of the padded test case is not padded")
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			claims, err := common.ParseToken(test.token)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("ParseToken returned %v, want an error containing %q", err, test.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseToken: %v", err)
+			}
+
+			if claims.Realm != "Certificate" || claims.Subject() != "ab" {
+				t.Errorf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
+
+func TestAccountID(t *testing.T) {
+
+	tests := []struct {
+		realm     string
+		data      map[string]string
+		accountID string
+		err       string
+	}{
+		{"AWSSecurityToken", map[string]string{"organization": "123456789012"}, "123456789012", ""},
+		{"GCPIdentityToken", map[string]string{"projectnumber": "987654321"}, "987654321", ""},
+		{"AzureIdentityToken", map[string]string{"subscriptionid": "00000000-0000-0000-0000-000000000000"}, "00000000-0000-0000-0000-000000000000", ""},
+		{"AWSSecurityToken", nil, "", "unable to get cloud account ID"},
+		{"Certificate", map[string]string{"organization": "acme"}, "", "realm Certificate has no cloud account"},
+		{"Unknown", nil, "", "realm Unknown has no cloud account"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.realm, func(t *testing.T) {
+
+			claims := &common.Claims{Realm: test.realm, Data: test.data}
+
+			accountID, err := claims.AccountID()
+
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Errorf("AccountID returned %q, %v, want the error %q", accountID, err, test.err)
+				}
+				return
+			}
+
+			if err != nil || accountID != test.accountID {
+				t.Errorf("AccountID returned %q, %v, want %q", accountID, err, test.accountID)
+			}
+		})
+	}
+}
+
+func TestIsExpired(t *testing.T) {
+
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		exp     time.Time
+		skew    time.Duration
+		expired bool
+	}{
+		{"valid", now.Add(time.Hour), 0, false},
+		{"valid beyond skew", now.Add(time.Hour), 30 * time.Minute, false},
+		{"within skew", now.Add(time.Minute), 5 * time.Minute, true},
+		{"expired", now.Add(-time.Minute), 0, true},
+		{"no exp", time.Unix(0, 0), 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			claims := &common.Claims{Exp: test.exp.Unix()}
+
+			if expired := claims.IsExpired(test.skew); expired != test.expired {
+				t.Errorf("IsExpired(%s) returned %v, want %v", test.skew, expired, test.expired)
+			}
+		})
+	}
+}
+
+func TestNamespace(t *testing.T) {
+
+	claims, err := common.ParseToken(jwt(base64.RawURLEncoding, `{"realm":"Certificate","restrictions":{"namespace":"/tenant/child"}}`))
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+
+	if ns := claims.Namespace(); ns != "/tenant/child" {
+		t.Errorf("Namespace returned %q, want /tenant/child", ns)
+	}
+
+	claims, err = common.ParseToken(jwt(base64.RawURLEncoding, `{"realm":"Certificate"}`))
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+
+	if ns := claims.Namespace(); ns != "" {
+		t.Errorf("Namespace of an unrestricted token returned %q, want an empty string", ns)
+	}
+}
@@ -0,0 +1,108 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// IssueRequest is the body of a request to /issue. Metadata is the proof of identity of the realm;
+// it is omitted if nil.
+type IssueRequest struct {
+	Realm string `json:"realm"`
+	IssueOptions
+	Metadata interface{} `json:"metadata,omitempty"`
+}
+
+// Issuer exchanges a proof of identity for a token at the /issue endpoint of API. Provider names
+// the token provider in the telemetry.
+type Issuer struct {
+	API             string
+	Provider        string
+	HTTPClient      *http.Client
+	RetryPolicy     *retry.Policy
+	IssueOptions    IssueOptions
+	Instrumentation *telemetry.Instrumentation
+	Logger          *zap.Logger
+}
+
+// Issue returns a new token for realm. An error is returned if the API does not answer with 200 or
+// the token has already expired.
+func (t *Issuer) Issue(ctx context.Context, realm string, metadata interface{}) (*PrismaToken, error) {
+
+	t.Logger.Debug("entering Issue")
+
+	jsonReq, err := json.Marshal(&IssueRequest{
+		Realm:        realm,
+		IssueOptions: t.IssueOptions,
+		Metadata:     metadata,
+	})
+	if err != nil {
+		t.Logger.Debug("returning Issue with error(s)")
+		return nil, err
+	}
+
+	ctx, done := t.Instrumentation.StartTokenIssue(ctx, t.Provider, realm)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", t.API+"/issue", bytes.NewBuffer(jsonReq))
+	if err != nil {
+		done(nil, err)
+		t.Logger.Debug("returning Issue with error(s)")
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+
+	// Issuing a token has no side effects so the request may be retried
+	req.Header["Idempotency-Key"] = nil
+
+	resp, err := t.RetryPolicy.Do(req, t.HTTPClient.Do)
+	done(resp, err)
+	if err != nil {
+		t.Logger.Debug("returning Issue with error(s)")
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Logger.Debug("returning Issue with error(s)")
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		t.Logger.Debug("returning Issue with error(s)")
+		return nil, prisma_types.NewAPIErrorWithCode(resp.StatusCode, respBytes)
+	}
+
+	var token *PrismaToken
+	err = json.Unmarshal(respBytes, &token)
+	if err != nil {
+		t.Logger.Debug("returning Issue with error(s)")
+		return nil, err
+	}
+
+	if token == nil {
+		t.Logger.Debug("returning Issue with error(s)")
+		return nil, fmt.Errorf("no token in the response of /issue")
+	}
+
+	err = TokenExpired(token.Claims.Exp)
+	if err != nil {
+		t.Logger.Debug("returning Issue with error(s)")
+		return nil, err
+	}
+
+	t.Logger.Debug("returning Issue")
+	return token, nil
+}
@@ -0,0 +1,73 @@
+package common_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	"github.com/aporeto-se/prisma-sdk-go-v2/retry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+	"github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+func newIssuer(server *prismatest.Server) *common.Issuer {
+	return &common.Issuer{
+		API:             server.URL,
+		Provider:        "test",
+		HTTPClient:      http.DefaultClient,
+		RetryPolicy:     retry.NewPolicy(),
+		IssueOptions:    common.IssueOptions{Validity: common.DefaultValidity.String()},
+		Instrumentation: telemetry.New(nil, nil),
+		Logger:          zap.NewNop(),
+	}
+}
+
+func TestIssue(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	token, err := newIssuer(server).Issue(context.Background(), "Certificate", nil)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	if token.Claims.Realm != "Certificate" || token.Validity != "12h0m0s" {
+		t.Errorf("unexpected token %+v", token)
+	}
+
+	// A realm without metadata does not send any
+	var body map[string]interface{}
+	err = json.Unmarshal(server.Requests()[0].Body, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := body["metadata"]; ok {
+		t.Errorf("unexpected metadata in /issue request %v", body)
+	}
+}
+
+func TestIssueError(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	server.AddFailure(&prismatest.Failure{
+		Path:       "/issue",
+		StatusCode: 403,
+	})
+
+	_, err := newIssuer(server).Issue(context.Background(), "Certificate", nil)
+
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 403 {
+		t.Errorf("Issue returned %v, want an APIError with code 403", err)
+	}
+}
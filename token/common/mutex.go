@@ -0,0 +1,56 @@
+package common
+
+import (
+	"context"
+	"sync"
+)
+
+// Mutex is a mutual exclusion lock that a caller can stop waiting for when its context is done.
+// The providers hold it while they fetch a new token so that only one fetch runs at a time; the
+// other callers wait for the result for as long as their context allows. The zero value is an
+// unlocked mutex.
+type Mutex struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+func (t *Mutex) init() {
+	t.once.Do(func() {
+		t.ch = make(chan struct{}, 1)
+	})
+}
+
+// Lock locks the mutex and waits until it is available
+func (t *Mutex) Lock() {
+	t.init()
+	t.ch <- struct{}{}
+}
+
+// LockContext locks the mutex and waits until it is available or ctx is done. The error of ctx is
+// returned if the mutex was not locked.
+func (t *Mutex) LockContext(ctx context.Context) error {
+
+	t.init()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case t.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Unlock unlocks the mutex. It panics if the mutex is not locked.
+func (t *Mutex) Unlock() {
+	t.init()
+	select {
+	case <-t.ch:
+	default:
+		panic("common: unlock of unlocked Mutex")
+	}
+}
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMutexLockContext(t *testing.T) {
+
+	var mutex Mutex
+
+	err := mutex.LockContext(context.Background())
+	if err != nil {
+		t.Fatalf("LockContext on unlocked mutex: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = mutex.LockContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("LockContext on locked mutex returned %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		mutex.Lock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("Lock returned while the mutex was locked")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	mutex.Unlock()
+	<-locked
+	mutex.Unlock()
+}
+
+func TestMutexUnlockUnlocked(t *testing.T) {
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Unlock of unlocked mutex did not panic")
+		}
+	}()
+
+	var mutex Mutex
+	mutex.Unlock()
+}
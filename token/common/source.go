@@ -0,0 +1,162 @@
+package common
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// IssueFunc issues a new token
+type IssueFunc func(ctx context.Context) (*PrismaToken, error)
+
+// KeyFunc returns the cache key of the tokens issued by an IssueFunc. It is only called if a
+// cache is set.
+type KeyFunc func(ctx context.Context) (string, error)
+
+// StaticKey returns a KeyFunc that always returns key
+func StaticKey(key string) KeyFunc {
+	return func(context.Context) (string, error) {
+		return key, nil
+	}
+}
+
+// Source holds the token of a token provider and issues a new one when it has expired or was
+// invalidated. If a cache is set the tokens are shared with other processes through it. It is
+// safe for concurrent use; a token is issued by one caller at a time and the other callers wait
+// for the result until their context is done.
+type Source struct {
+	cache  TokenCache
+	key    KeyFunc
+	issue  IssueFunc
+	logger *zap.Logger
+
+	mutex Mutex
+	token *PrismaToken
+	evict bool
+}
+
+// NewSource returns new Source. The cache may be nil.
+func NewSource(cache TokenCache, key KeyFunc, issue IssueFunc, logger *zap.Logger) *Source {
+	return &Source{
+		cache:  cache,
+		key:    key,
+		issue:  issue,
+		logger: logger,
+	}
+}
+
+// Token returns the current token or a new one if it has expired or was invalidated
+func (t *Source) Token(ctx context.Context) (*PrismaToken, error) {
+
+	err := t.mutex.LockContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer t.mutex.Unlock()
+
+	if t.token != nil && TokenExpired(t.token.Claims.Exp) == nil {
+		return t.token, nil
+	}
+
+	token, err := t.cachedIssue(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	t.token = token
+
+	return token, nil
+}
+
+// Refresh returns a new token even if the current one is still valid. Unlike Invalidate it does
+// not evict the current token from the cache, so other processes may keep using it. A token
+// another process cached in the meantime is used if it expires later than the current one.
+func (t *Source) Refresh(ctx context.Context) (*PrismaToken, error) {
+
+	err := t.mutex.LockContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer t.mutex.Unlock()
+
+	var exp int64
+	if t.token != nil {
+		exp = t.token.Claims.Exp
+	}
+
+	token, err := t.cachedIssue(ctx, exp)
+	if err != nil {
+		return nil, err
+	}
+
+	t.token = token
+
+	return token, nil
+}
+
+// Invalidate discards the current token so that the next call to Token issues a new one. The
+// token may have been revoked, so it is also evicted from the cache before then.
+func (t *Source) Invalidate() {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.logger.Debug("token invalidated")
+	t.token = nil
+	t.evict = t.cache != nil
+}
+
+// cachedIssue returns the token cached for the key if it expires after exp or issues and caches a
+// new one. The entry is locked while the token is issued so that only one process issues a token
+// for the key at a time.
+func (t *Source) cachedIssue(ctx context.Context, exp int64) (*PrismaToken, error) {
+
+	if t.cache == nil {
+		return t.issue(ctx)
+	}
+
+	key, err := t.key(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.evict {
+		err = t.cache.Delete(ctx, key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			t.logger.Warn("token not evicted from token cache", zap.Error(err))
+		}
+		t.evict = false
+	}
+
+	unlock, err := t.cache.Lock(ctx, key)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		t.logger.Warn("token cache not locked", zap.Error(err))
+	} else {
+		defer unlock()
+	}
+
+	// Another process may have issued a token while this one waited for the lock
+	token := t.cache.Get(key)
+	if token != nil && token.Claims.Exp > exp {
+		t.logger.Debug("token found in token cache")
+		return token, nil
+	}
+
+	token, err = t.issue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.cache.Put(key, token)
+	if err != nil {
+		t.logger.Warn("token not cached", zap.Error(err))
+	}
+
+	return token, nil
+}
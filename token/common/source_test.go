@@ -0,0 +1,238 @@
+package common_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+// issuer issues numbered tokens. Issue waits until block is closed if it is set and signals
+// issuing when it starts.
+type issuer struct {
+	issued  int32
+	block   chan struct{}
+	issuing chan struct{}
+}
+
+func (t *issuer) issue(ctx context.Context) (*common.PrismaToken, error) {
+
+	n := atomic.AddInt32(&t.issued, 1)
+
+	if t.issuing != nil {
+		t.issuing <- struct{}{}
+	}
+
+	if t.block != nil {
+		<-t.block
+	}
+
+	// Every token expires later than the ones issued before it
+	token := &common.PrismaToken{Token: fmt.Sprintf("token-%d", n)}
+	token.Claims.Exp = time.Now().Add(time.Hour + time.Duration(n)*time.Second).Unix()
+	return token, nil
+}
+
+// memoryCache is a TokenCache in memory
+type memoryCache struct {
+	mutex   sync.Mutex
+	tokens  map[string]*common.PrismaToken
+	deleted []string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{tokens: make(map[string]*common.PrismaToken)}
+}
+
+func (t *memoryCache) Lock(ctx context.Context, key string) (func(), error) {
+	return func() {}, nil
+}
+
+func (t *memoryCache) Get(key string) *common.PrismaToken {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.tokens[key]
+}
+
+func (t *memoryCache) Put(key string, token *common.PrismaToken) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.tokens[key] = token
+	return nil
+}
+
+func (t *memoryCache) Delete(ctx context.Context, key string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.tokens, key)
+	t.deleted = append(t.deleted, key)
+	return nil
+}
+
+func TestSourceConcurrentToken(t *testing.T) {
+
+	issuer := &issuer{}
+	source := common.NewSource(nil, nil, issuer.issue, zap.NewNop())
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 50)
+
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			token, err := source.Token(context.Background())
+			if err != nil {
+				t.Errorf("Token: %v", err)
+				return
+			}
+			tokens[i] = token.Token
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, v := range tokens {
+		if v != tokens[0] {
+			t.Fatalf("callers got different tokens")
+		}
+	}
+
+	if n := atomic.LoadInt32(&issuer.issued); n != 1 {
+		t.Errorf("%d tokens were issued, want 1", n)
+	}
+}
+
+func TestSourceTokenWaitHonorsContext(t *testing.T) {
+
+	issuer := &issuer{
+		block:   make(chan struct{}),
+		issuing: make(chan struct{}, 1),
+	}
+	source := common.NewSource(nil, nil, issuer.issue, zap.NewNop())
+
+	// The first caller issues the token and is held up
+	first := make(chan error, 1)
+	go func() {
+		_, err := source.Token(context.Background())
+		first <- err
+	}()
+
+	<-issuer.issuing
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := source.Token(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting caller returned %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(issuer.block)
+
+	if err := <-first; err != nil {
+		t.Errorf("first caller: %v", err)
+	}
+}
+
+func TestSourceCache(t *testing.T) {
+
+	cache := newMemoryCache()
+	issuer := &issuer{}
+
+	// Each source stands for a process that shares the cache
+	newSource := func() *common.Source {
+		return common.NewSource(cache, common.StaticKey("key"), issuer.issue, zap.NewNop())
+	}
+
+	first, err := newSource().Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	source := newSource()
+
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	if token.Token != first.Token {
+		t.Errorf("token was not read from the cache")
+	}
+
+	// An invalidated token may have been revoked, so it is not read back from the cache
+	source.Invalidate()
+
+	token, err = source.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token after Invalidate: %v", err)
+	}
+
+	if token.Token == first.Token {
+		t.Errorf("Token after Invalidate returned the invalidated token")
+	}
+
+	if len(cache.deleted) != 1 || cache.deleted[0] != "key" {
+		t.Errorf("deleted %v, want [key]", cache.deleted)
+	}
+
+	if cached := cache.Get("key"); cached == nil || cached.Token != token.Token {
+		t.Errorf("new token was not cached")
+	}
+
+	if n := atomic.LoadInt32(&issuer.issued); n != 2 {
+		t.Errorf("%d tokens were issued, want 2", n)
+	}
+}
+
+func TestSourceRefresh(t *testing.T) {
+
+	cache := newMemoryCache()
+	issuer := &issuer{}
+
+	first := common.NewSource(cache, common.StaticKey("key"), issuer.issue, zap.NewNop())
+	second := common.NewSource(cache, common.StaticKey("key"), issuer.issue, zap.NewNop())
+
+	for _, v := range []*common.Source{first, second} {
+		if _, err := v.Token(context.Background()); err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+	}
+
+	// Refresh issues a new token but leaves the current one to the other processes
+	token, err := first.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	if token.Token != "token-2" {
+		t.Errorf("Refresh returned %q, want token-2", token.Token)
+	}
+
+	if len(cache.deleted) != 0 {
+		t.Errorf("Refresh deleted %v from the cache", cache.deleted)
+	}
+
+	// The token another process cached expires later, so it is used instead of issuing one
+	token, err = second.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	if token.Token != "token-2" {
+		t.Errorf("Refresh returned %q, want the cached token-2", token.Token)
+	}
+
+	if n := atomic.LoadInt32(&issuer.issued); n != 2 {
+		t.Errorf("%d tokens were issued, want 2", n)
+	}
+}
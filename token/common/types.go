@@ -22,42 +22,45 @@ type PrismaToken struct {
 			Projectid       string `json:"projectid,omitempty" yaml:"projectid,omitempty"`
 			Projectnumber   string `json:"projectnumber,omitempty" yaml:"projectnumber,omitempty"`
 			Zone            string `json:"zone,omitempty" yaml:"zone,omitempty"`
+			Subscriptionid  string `json:"subscriptionid,omitempty" yaml:"subscriptionid,omitempty"`
+			Tenantid        string `json:"tenantid,omitempty" yaml:"tenantid,omitempty"`
+			Objectid        string `json:"objectid,omitempty" yaml:"objectid,omitempty"`
 		} `json:"data,omitempty" yaml:"data,omitempty"`
-		Exp          int64  `json:"exp,omitempty" yaml:"exp,omitempty"`
-		Iat          int64  `json:"iat,omitempty" yaml:"iat,omitempty"`
-		Iss          string `json:"iss,omitempty" yaml:"iss,omitempty"`
-		Realm        string `json:"realm,omitempty" yaml:"realm,omitempty"`
-		Restrictions struct {
-		} `json:"restrictions,omitempty" yaml:"restrictions,omitempty"`
-		Sub string `json:"sub,omitempty" yaml:"sub,omitempty"`
+		Exp          int64        `json:"exp,omitempty" yaml:"exp,omitempty"`
+		Iat          int64        `json:"iat,omitempty" yaml:"iat,omitempty"`
+		Iss          string       `json:"iss,omitempty" yaml:"iss,omitempty"`
+		Realm        string       `json:"realm,omitempty" yaml:"realm,omitempty"`
+		Restrictions Restrictions `json:"restrictions,omitempty" yaml:"restrictions,omitempty"`
+		Sub          string       `json:"sub,omitempty" yaml:"sub,omitempty"`
 	} `json:"claims,omitempty" yaml:"claims,omitempty"`
 	Data     string      `json:"data,omitempty" yaml:"data,omitempty"`
 	Metadata interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	Opaque   struct {
 	} `json:"opaque,omitempty" yaml:"opaque,omitempty"`
-	Quota                 int           `json:"quota,omitempty" yaml:"quota,omitempty"`
-	Realm                 string        `json:"realm,omitempty" yaml:"realm,omitempty"`
-	RestrictedNamespace   string        `json:"restrictedNamespace,omitempty" yaml:"restrictedNamespace,omitempty"`
-	RestrictedNetworks    []interface{} `json:"restrictedNetworks,omitempty" yaml:"restrictedNetworks,omitempty"`
-	RestrictedPermissions []interface{} `json:"restrictedPermissions,omitempty" yaml:"restrictedPermissions,omitempty"`
-	Token                 string        `json:"token,omitempty" yaml:"token,omitempty"`
-	Validity              string        `json:"validity,omitempty" yaml:"validity,omitempty"`
+	Quota                 int      `json:"quota,omitempty" yaml:"quota,omitempty"`
+	Realm                 string   `json:"realm,omitempty" yaml:"realm,omitempty"`
+	RestrictedNamespace   string   `json:"restrictedNamespace,omitempty" yaml:"restrictedNamespace,omitempty"`
+	RestrictedNetworks    []string `json:"restrictedNetworks,omitempty" yaml:"restrictedNetworks,omitempty"`
+	RestrictedPermissions []string `json:"restrictedPermissions,omitempty" yaml:"restrictedPermissions,omitempty"`
+	Token                 string   `json:"token,omitempty" yaml:"token,omitempty"`
+	Validity              string   `json:"validity,omitempty" yaml:"validity,omitempty"`
 }
 
-// OAuthToken OAuth Token
-type OAuthToken struct {
-	Realm string `json:"realm"`
-	Data  struct {
-		CommonName   string `json:"commonName"`
-		Organization string `json:"organization"`
-		Realm        string `json:"realm"`
-		SerialNumber string `json:"serialNumber"`
-		Subject      string `json:"subject"`
-	} `json:"data"`
-	Restrictions struct {
-	} `json:"restrictions"`
-	Exp int64  `json:"exp"`
-	Iat int64  `json:"iat"`
-	Iss string `json:"iss"`
-	Sub string `json:"sub"`
+// Restrictions are the restrictions of a token. A restricted token can only be used in the
+// namespace, from the networks and with the permissions listed.
+type Restrictions struct {
+	Namespace   string   `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Networks    []string `json:"networks,omitempty" yaml:"networks,omitempty"`
+	Permissions []string `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+}
+
+// IssueOptions are the parameters of a request to /issue that do not depend on the realm. They
+// are embedded in IssueRequest.
+type IssueOptions struct {
+	Validity              string   `json:"validity"`
+	Quota                 int      `json:"quota"`
+	Audience              string   `json:"audience,omitempty"`
+	RestrictedNamespace   string   `json:"restrictedNamespace,omitempty"`
+	RestrictedNetworks    []string `json:"restrictedNetworks,omitempty"`
+	RestrictedPermissions []string `json:"restrictedPermissions,omitempty"`
 }
@@ -13,3 +13,6 @@ func TokenExpired(exp int64) error {
 	}
 	return nil
 }
+
+// DefaultValidity is the validity requested from /issue when none is configured
+const DefaultValidity = 12 * time.Hour
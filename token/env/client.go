@@ -10,11 +10,8 @@ already set and existing as env vars.
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
 
 	"go.uber.org/zap"
 
@@ -24,97 +21,93 @@ import (
 // Client the token client
 type Client struct {
 	tokenString string
-	token       *common.OAuthToken
+	claims      *common.Claims
 }
 
 // NewClient returns new Client
 func NewClient(config *Config) (*Client, error) {
 
-	zap.L().Debug("entering NewClient")
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
 
 	if config.TokenString != "" {
-		zap.L().Debug("token set in config")
+		logger.Debug("token set in config")
 		return newClient(config.TokenString)
 	}
 
-	zap.L().Debug("token not set in config, will attempt to find in env var")
+	logger.Debug("token not set in config, will attempt to find in env var")
 
-	tokenString := getTokenStringFromEnv()
+	tokenString := getTokenStringFromEnv(logger)
 
 	if tokenString != "" {
-		zap.L().Debug("using token from env vars")
+		logger.Debug("using token from env vars")
 		return newClient(tokenString)
 	}
 
-	zap.L().Debug("token not set in config or env vars")
+	logger.Debug("token not set in config or env vars")
 
 	return nil, fmt.Errorf("token not set in config or found in env vars")
 }
 
 func newClient(tokenString string) (*Client, error) {
 
-	tokenStringSlice := strings.Split(tokenString, ".")
-	if len(tokenStringSlice) != 3 {
-		return nil, fmt.Errorf("attribute tokenString is invalid")
-	}
-
-	tokenBytes, err := base64.RawURLEncoding.DecodeString(tokenStringSlice[1])
-	if err != nil {
-		return nil, err
-	}
-
-	var token *common.OAuthToken
-	err = json.Unmarshal(tokenBytes, &token)
+	claims, err := common.ParseToken(tokenString)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("attribute tokenString is invalid: %w", err)
 	}
 
 	return &Client{
 		tokenString: tokenString,
-		token:       token,
+		claims:      claims,
 	}, nil
 
 }
 
-func getTokenStringFromEnv() string {
+func getTokenStringFromEnv(logger *zap.Logger) string {
 
-	tokenString := getTokenStringFromEnvVar(PrismaTokenEnv)
+	tokenString := getTokenStringFromEnvVar(PrismaTokenEnv, logger)
 	if tokenString != "" {
 		return tokenString
 	}
 
-	tokenString = getTokenStringFromEnvVar(ApoctlTokenEnv)
+	tokenString = getTokenStringFromEnvVar(ApoctlTokenEnv, logger)
 	if tokenString != "" {
 		return tokenString
 	}
 
-	tokenString = getTokenStringFromEnvVar(EnforcerdTokenEnv)
+	tokenString = getTokenStringFromEnvVar(EnforcerdTokenEnv, logger)
 	if tokenString != "" {
 		return tokenString
 	}
 
-	zap.L().Debug(fmt.Sprintf("token not found in env var %s, %s, or %s", PrismaTokenEnv, ApoctlTokenEnv, EnforcerdTokenEnv))
+	logger.Debug(fmt.Sprintf("token not found in env var %s, %s, or %s", PrismaTokenEnv, ApoctlTokenEnv, EnforcerdTokenEnv))
 
 	return ""
 }
 
-func getTokenStringFromEnvVar(v string) string {
+func getTokenStringFromEnvVar(v string, logger *zap.Logger) string {
 	r := os.Getenv(v)
 	if r != "" {
-		zap.L().Debug(fmt.Sprintf("got token from env var %s", v))
+		logger.Debug(fmt.Sprintf("got token from env var %s", v))
 	}
 	return r
 }
 
-// AccountID returns Cloud Account ID or error
+// AccountID returns Cloud Account ID or error. Only tokens issued in a cloud realm have one.
 func (t *Client) AccountID(ctx context.Context) (string, error) {
-	return "", fmt.Errorf("this implementation does not support this function")
+	return t.claims.AccountID()
+}
+
+// Claims returns the claims of the token
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
+	return t.claims, nil
 }
 
 // Token returns the token or an error
 func (t *Client) Token(ctx context.Context) (string, error) {
 
-	err := common.TokenExpired(t.token.Exp)
+	err := common.TokenExpired(t.claims.Exp)
 
 	if err != nil {
 		return "", err
@@ -0,0 +1,35 @@
+package token_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	token_env "github.com/aporeto-se/prisma-sdk-go-v2/token/env"
+)
+
+func TestAccountID(t *testing.T) {
+
+	exp := time.Now().Add(time.Hour).Unix()
+	payload := fmt.Sprintf(`{"realm":"AWSSecurityToken","data":{"organization":"123456789012"},"exp":%d}`, exp)
+	tokenString := "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+
+	client, err := token_env.NewConfig().
+		SetTokenString(tokenString).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	accountID, err := client.AccountID(context.Background())
+	if err != nil || accountID != "123456789012" {
+		t.Errorf("AccountID returned %q, %v, want 123456789012", accountID, err)
+	}
+
+	token, err := client.Token(context.Background())
+	if err != nil || token != tokenString {
+		t.Errorf("Token returned %q, %v", token, err)
+	}
+}
@@ -1,8 +1,13 @@
 package token
 
+import (
+	"go.uber.org/zap"
+)
+
 // Config config
 type Config struct {
 	TokenString string
+	Logger      *zap.Logger
 }
 
 // NewConfig returns new Config
@@ -15,3 +20,24 @@ func (t *Config) SetTokenString(v string) *Config {
 	t.TokenString = v
 	return t
 }
+
+// SetLogger sets entity and returns self. If not set nothing is logged.
+func (t *Config) SetLogger(logger *zap.Logger) *Config {
+	t.Logger = logger
+	return t
+}
+
+// GetLogger returns entity. If entity is nil a no-op logger is returned.
+func (t *Config) GetLogger() *zap.Logger {
+
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+
+	return t.Logger
+}
+
+// Build returns entity
+func (t *Config) Build() (*Client, error) {
+	return NewClient(t)
+}
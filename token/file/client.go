@@ -0,0 +1,265 @@
+package token
+
+/*
+
+This implements the TokenProvider Interface and provides Prisma tokens read from a file, such
+as the tokens written by a Kubernetes sidecar or a Vault agent. The file is checked for changes
+every PollInterval and a rotated token is used as soon as it is read. The token is parsed the
+same way as by the env provider; if the file holds an invalid token, or an expired token while the
+current one is still valid, the last good token is kept until the file changes again.
+
+If the current token has expired or was invalidated Token reads the file again without waiting
+for the next check. Close stops the background goroutine.
+
+type TokenProvider interface {
+	Token(context.Context) (string, error)
+	AccountID(ctx context.Context) (string, error)
+}
+
+*/
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+	token_env "github.com/aporeto-se/prisma-sdk-go-v2/token/env"
+)
+
+// Client the token client. It is safe for concurrent use.
+type Client struct {
+	path         string
+	pollInterval time.Duration
+	logger       *zap.Logger
+
+	mutex   sync.Mutex
+	token   *token_env.Client
+	modTime time.Time
+	size    int64
+	stale   bool
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClient returns new Client. The token file must exist and hold a valid token.
+func NewClient(config *Config) (*Client, error) {
+
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
+
+	var errors *multierror.Error
+
+	if config.Path == "" {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Path is required"))
+	}
+
+	if config.PollInterval < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute PollInterval must not be negative"))
+	}
+
+	err := errors.ErrorOrNil()
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	client := &Client{
+		path:         config.Path,
+		pollInterval: config.GetPollInterval(),
+		logger:       logger,
+		done:         make(chan struct{}),
+	}
+
+	_, err = client.load(true)
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client.cancel = cancel
+
+	go client.run(ctx)
+
+	logger.Debug("returning NewClient")
+	return client, nil
+}
+
+// Token returns the token of the file or an error if it has expired
+func (t *Client) Token(ctx context.Context) (string, error) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.stale {
+		t.reload()
+	}
+
+	token, err := t.token.Token(ctx)
+	if err == nil {
+		return token, nil
+	}
+
+	// The file may have been rotated since the last check
+	if !t.reload() {
+		return "", err
+	}
+
+	return t.token.Token(ctx)
+}
+
+// AccountID returns Cloud Account ID or error
+func (t *Client) AccountID(ctx context.Context) (string, error) {
+
+	t.mutex.Lock()
+	token := t.token
+	t.mutex.Unlock()
+
+	return token.AccountID(ctx)
+}
+
+// Claims returns the claims of the current token
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
+
+	t.mutex.Lock()
+	token := t.token
+	t.mutex.Unlock()
+
+	return token.Claims(ctx)
+}
+
+// Invalidate makes the next call to Token read the file again even if it has not changed
+func (t *Client) Invalidate() {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.logger.Debug("token invalidated")
+	t.stale = true
+}
+
+// Close stops checking the file for changes and waits for the background goroutine to return.
+// The client may still be used afterwards; the file is then only read when the token has expired
+// or was invalidated.
+func (t *Client) Close() error {
+
+	t.closeOnce.Do(func() {
+		t.cancel()
+		<-t.done
+	})
+
+	return nil
+}
+
+func (t *Client) run(ctx context.Context) {
+
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+
+		select {
+
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+
+		t.mutex.Lock()
+
+		_, err := t.load(false)
+		if err != nil {
+			t.logger.Warn("token file not reloaded; keeping the last good token", zap.String("path", t.path), zap.Error(err))
+		}
+
+		t.mutex.Unlock()
+	}
+}
+
+// reload reads the file even if it has not changed and returns true if a new token was loaded.
+// It must be called with the mutex held.
+func (t *Client) reload() bool {
+
+	loaded, err := t.load(true)
+	if err != nil {
+		t.logger.Warn("token file not reloaded; keeping the last good token", zap.String("path", t.path), zap.Error(err))
+	}
+
+	return loaded
+}
+
+// load reads the file if it has changed since it was last read or if force is set and returns true
+// if a new token was loaded. The current token is only replaced by a valid token, and a current
+// token that has not expired only by one that has not expired either. It must be called with the
+// mutex held.
+func (t *Client) load(force bool) (bool, error) {
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return false, err
+	}
+
+	if !force && info.ModTime().Equal(t.modTime) && info.Size() == t.size {
+		return false, nil
+	}
+
+	// An invalid file is not read again until it changes
+	t.modTime = info.ModTime()
+	t.size = info.Size()
+	t.stale = false
+
+	data, err := ioutil.ReadFile(t.path)
+	if err != nil {
+		return false, err
+	}
+
+	tokenString := strings.TrimSpace(string(data))
+	if tokenString == "" {
+		return false, fmt.Errorf("token file %s is empty", t.path)
+	}
+
+	currentValid := false
+
+	if t.token != nil {
+		current, err := t.token.Token(context.Background())
+		if err == nil && current == tokenString {
+			return false, nil
+		}
+		currentValid = err == nil
+	}
+
+	token, err := token_env.NewConfig().
+		SetTokenString(tokenString).
+		SetLogger(t.logger).
+		Build()
+	if err != nil {
+		return false, fmt.Errorf("token file %s is invalid: %w", t.path, err)
+	}
+
+	// A file that has not been rotated yet must not replace a token that is still valid
+	if currentValid {
+		_, err = token.Token(context.Background())
+		if err != nil {
+			return false, fmt.Errorf("token file %s holds an expired token: %w", t.path, err)
+		}
+	}
+
+	t.token = token
+	t.logger.Info("token loaded from file", zap.String("path", t.path))
+
+	return true, nil
+}
@@ -0,0 +1,142 @@
+package token_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	token_file "github.com/aporeto-se/prisma-sdk-go-v2/token/file"
+)
+
+// newToken returns an unsigned JWT with the subject and expiry
+func newToken(subject string, exp time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"realm":"Certificate","sub":%q,"iat":%d,"exp":%d}`,
+		subject, time.Now().Unix(), exp.Unix())))
+	return header + "." + claims + ".test"
+}
+
+func writeToken(t *testing.T, path, token string) {
+
+	t.Helper()
+
+	err := ioutil.WriteFile(path, []byte(token+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newClient(t *testing.T, path string, logger *zap.Logger) *token_file.Client {
+
+	t.Helper()
+
+	client, err := token_file.NewConfig().
+		SetPath(path).
+		SetPollInterval(time.Hour).
+		SetLogger(logger).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	return client
+}
+
+func TestRotatedToken(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "token")
+
+	first := newToken("first", time.Now().Add(time.Hour))
+	writeToken(t, path, first)
+
+	client := newClient(t, path, zap.NewNop())
+	defer client.Close()
+
+	token, err := client.Token(context.Background())
+	if err != nil || token != first {
+		t.Fatalf("Token returned %q, %v; want the first token", token, err)
+	}
+
+	second := newToken("second", time.Now().Add(time.Hour))
+	writeToken(t, path, second)
+	client.Invalidate()
+
+	token, err = client.Token(context.Background())
+	if err != nil || token != second {
+		t.Fatalf("Token returned %q, %v; want the second token", token, err)
+	}
+}
+
+func TestExpiredTokenNotLoaded(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "token")
+
+	current := newToken("current", time.Now().Add(time.Hour))
+	writeToken(t, path, current)
+
+	core, logs := observer.New(zapcore.WarnLevel)
+
+	client := newClient(t, path, zap.New(core))
+	defer client.Close()
+
+	writeToken(t, path, newToken("expired", time.Now().Add(-time.Hour)))
+	client.Invalidate()
+
+	token, err := client.Token(context.Background())
+	if err != nil || token != current {
+		t.Fatalf("Token returned %q, %v; want the current token", token, err)
+	}
+
+	if n := logs.FilterMessage("token file not reloaded; keeping the last good token").Len(); n != 1 {
+		t.Errorf("expired token file was logged %d times, want 1", n)
+	}
+
+	// A malformed file is logged the same way
+	writeToken(t, path, "malformed")
+	client.Invalidate()
+
+	token, err = client.Token(context.Background())
+	if err != nil || token != current {
+		t.Fatalf("Token returned %q, %v; want the current token", token, err)
+	}
+
+	if n := logs.FilterMessage("token file not reloaded; keeping the last good token").Len(); n != 2 {
+		t.Errorf("malformed token file was logged %d times, want 1", n-1)
+	}
+}
+
+func TestExpiredTokenReplacesExpired(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "token")
+
+	writeToken(t, path, newToken("current", time.Now().Add(-time.Minute)))
+
+	client := newClient(t, path, zap.NewNop())
+	defer client.Close()
+
+	// Both tokens have expired; the newer file is still loaded
+	writeToken(t, path, newToken("rotated", time.Now().Add(-time.Second)))
+	client.Invalidate()
+
+	_, err := client.Token(context.Background())
+	if err == nil {
+		t.Fatal("Token returned no error for an expired token")
+	}
+
+	claims, err := client.Claims(context.Background())
+	if err != nil {
+		t.Fatalf("Claims: %v", err)
+	}
+
+	if claims.Sub != "rotated" {
+		t.Errorf("subject is %q, want rotated", claims.Sub)
+	}
+}
@@ -0,0 +1,66 @@
+package token
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultPollInterval is the default interval at which the token file is checked for changes
+const DefaultPollInterval = 10 * time.Second
+
+// Config config
+type Config struct {
+	Path         string
+	PollInterval time.Duration
+	Logger       *zap.Logger
+}
+
+// NewConfig returns new Config
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// SetPath sets the path of the token file and returns self
+func (t *Config) SetPath(path string) *Config {
+	t.Path = path
+	return t
+}
+
+// SetPollInterval sets the interval at which the token file is checked for changes and returns
+// self. If not set DefaultPollInterval is used.
+func (t *Config) SetPollInterval(pollInterval time.Duration) *Config {
+	t.PollInterval = pollInterval
+	return t
+}
+
+// GetPollInterval returns attribute. If not set DefaultPollInterval is returned.
+func (t *Config) GetPollInterval() time.Duration {
+
+	if t.PollInterval == 0 {
+		return DefaultPollInterval
+	}
+
+	return t.PollInterval
+}
+
+// SetLogger sets entity and returns self. If not set nothing is logged.
+func (t *Config) SetLogger(logger *zap.Logger) *Config {
+	t.Logger = logger
+	return t
+}
+
+// GetLogger returns entity. If entity is nil a no-op logger is returned.
+func (t *Config) GetLogger() *zap.Logger {
+
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+
+	return t.Logger
+}
+
+// Build returns entity
+func (t *Config) Build() (*Client, error) {
+	return NewClient(t)
+}
@@ -14,166 +14,171 @@ type TokenProvider interface {
 */
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 
 	"cloud.google.com/go/compute/metadata"
 	"go.uber.org/zap"
 
+	"github.com/aporeto-se/prisma-sdk-go-v2/telemetry"
 	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
-	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
 )
 
 var (
 	identitySuffix = "instance/service-accounts/default/identity?audience=aporeto&format=full"
 )
 
-// Client the token client
+// Client the token client. It is safe for concurrent use.
 type Client struct {
-	api        string
-	httpClient *http.Client
-
-	token *common.PrismaToken
+	api          string
+	issueOptions common.IssueOptions
+	issuer       *common.Issuer
+	source       *common.Source
+	logger       *zap.Logger
 }
 
 // NewClient returns new Client
 func NewClient(config *Config) (*Client, error) {
 
-	zap.L().Debug("entering NewClient(config)")
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient(config)")
 
 	if config.API == "" {
 		return nil, fmt.Errorf("attribute API is required")
 	}
 
-	zap.L().Debug("returning NewClient(config)")
-	return &Client{
-		api:        config.API,
-		httpClient: config.GetHTTPClient(),
-	}, nil
+	if config.Validity < 0 {
+		return nil, fmt.Errorf("attribute Validity must not be negative")
+	}
+
+	if config.Quota < 0 {
+		return nil, fmt.Errorf("attribute Quota must not be negative")
+	}
+
+	client := &Client{
+		api:          config.API,
+		issueOptions: config.GetIssueOptions(),
+		issuer: &common.Issuer{
+			API:             config.API,
+			Provider:        "gcp",
+			HTTPClient:      config.GetHTTPClient(),
+			RetryPolicy:     config.GetRetryPolicy(),
+			IssueOptions:    config.GetIssueOptions(),
+			Instrumentation: telemetry.New(config.TracerProvider, config.Metrics),
+			Logger:          logger,
+		},
+		logger: logger,
+	}
+
+	client.source = common.NewSource(config.Cache, client.cacheKey, client.issueToken, logger)
+
+	logger.Debug("returning NewClient(config)")
+	return client, nil
 }
 
-type req struct {
-	Realm    string `json:"realm"`
-	Validity string `json:"validity"`
-	Quota    int    `json:"quota"`
-	Metadata struct {
-		Token string `json:"token"`
-	} `json:"metadata"`
+type identityMetadata struct {
+	Token string `json:"token"`
 }
 
-func (t *Client) initToken(ctx context.Context) error {
-
-	zap.L().Debug("entering initToken")
-
-	if t.token != nil {
-		zap.L().Debug("Token already exist")
-		err := common.TokenExpired(t.token.Claims.Exp)
-		if err != nil {
-			zap.L().Debug("Token is expired, fetching a new one")
-		} else {
-			zap.L().Debug("returning initToken")
-			return nil
-		}
-	} else {
-		zap.L().Debug("Token does not exist; fetching")
-	}
+// cacheKey returns the cache key of the tokens issued for the service account of the instance
+func (t *Client) cacheKey(ctx context.Context) (string, error) {
 
-	cloudToken, err := metadata.Get(identitySuffix)
+	email, err := metadata.Email("default")
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		return "", err
 	}
 
-	c := &req{
-		Realm:    "GCPIdentityToken",
-		Validity: "12h",
-	}
+	// The service account of the instance may be replaced while the instance is running
+	return common.CacheKey(t.api, "GCPIdentityToken", email, t.issueOptions), nil
+}
 
-	c.Metadata.Token = cloudToken
+// issueToken issues a new token for the identity token of the instance
+func (t *Client) issueToken(ctx context.Context) (*common.PrismaToken, error) {
 
-	jsonReq, _ := json.Marshal(c)
+	t.logger.Debug("entering issueToken")
 
-	req, err := http.NewRequestWithContext(ctx, "POST", t.api+"/issue", bytes.NewBuffer(jsonReq))
+	cloudToken, err := metadata.Get(identitySuffix)
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/json")
 
-	resp, err := t.httpClient.Do(req)
+	token, err := t.issuer.Issue(ctx, "GCPIdentityToken", &identityMetadata{
+		Token: cloudToken,
+	})
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		t.logger.Debug("returning issueToken with error(s)")
+		return nil, err
 	}
 
-	defer resp.Body.Close()
+	t.logger.Debug("returning issueToken")
+	return token, nil
+}
+
+// Token returns token string or error
+func (t *Client) Token(ctx context.Context) (string, error) {
 
-	respBytes, err := ioutil.ReadAll(resp.Body)
+	t.logger.Debug("entering GetToken")
 
+	token, err := t.source.Token(ctx)
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		t.logger.Debug("returning GetToken with error(s)")
+		return "", err
 	}
 
-	if resp.StatusCode != 200 {
-		return prisma_types.NewAPIError(respBytes)
-	}
+	t.logger.Debug("returning GetToken")
+	return token.Token, nil
+}
 
-	err = json.Unmarshal(respBytes, &t.token)
-	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
-	}
+// Invalidate discards the current token so that the next call to Token fetches a new one. The
+// token is also evicted from the cache if one is set.
+func (t *Client) Invalidate() {
+	t.source.Invalidate()
+}
+
+// Refresh returns a new token even if the current one is still valid. The current token is not
+// evicted from the cache; use Invalidate if it was rejected.
+func (t *Client) Refresh(ctx context.Context) (string, error) {
 
-	err = common.TokenExpired(t.token.Claims.Exp)
+	t.logger.Debug("entering Refresh")
+
+	token, err := t.source.Refresh(ctx)
 	if err != nil {
-		zap.L().Debug("returning initToken with error(s)")
-		return err
+		t.logger.Debug("returning Refresh with error(s)")
+		return "", err
 	}
 
-	zap.L().Debug("returning initToken")
-	return nil
+	t.logger.Debug("returning Refresh")
+	return token.Token, nil
 }
 
-// Token returns token string or error
-func (t *Client) Token(ctx context.Context) (string, error) {
+// Claims returns the claims of the current token
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
 
-	zap.L().Debug("entering GetToken")
+	t.logger.Debug("entering Claims")
 
-	err := t.initToken(ctx)
+	token, err := t.source.Token(ctx)
 	if err != nil {
-		zap.L().Debug("returning GetToken with error(s)")
-		return "", err
+		t.logger.Debug("returning Claims with error(s)")
+		return nil, err
 	}
 
-	zap.L().Debug("returning GetToken")
-	return t.token.Token, nil
+	t.logger.Debug("returning Claims")
+	return common.ParseToken(token.Token)
 }
 
 // AccountID returns Cloud Account ID or error
 func (t *Client) AccountID(ctx context.Context) (string, error) {
 
-	zap.L().Debug("entering AccountID")
+	t.logger.Debug("entering AccountID")
 
-	err := t.initToken(ctx)
+	claims, err := t.Claims(ctx)
 	if err != nil {
-		zap.L().Debug("returning AccountID with error(s)")
+		t.logger.Debug("returning AccountID with error(s)")
 		return "", err
 	}
 
-	// We use the Data.Projectnumber attribute for GCP
-	result := t.token.Claims.Data.Projectnumber
-
-	if result == "" {
-		zap.L().Debug("returning AccountID with error(s)")
-		return "", fmt.Errorf("unable to get cloud account ID")
-	}
-
-	zap.L().Debug("returning AccountID")
-	return result, nil
+	t.logger.Debug("returning AccountID")
+	return claims.AccountID()
 }
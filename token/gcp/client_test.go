@@ -0,0 +1,140 @@
+package token_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_cache "github.com/aporeto-se/prisma-sdk-go-v2/token/cache"
+	token_gcp "github.com/aporeto-se/prisma-sdk-go-v2/token/gcp"
+)
+
+// newMetadataServer returns a fake of the metadata server for the service account email and
+// points the metadata package at it
+func newMetadataServer(t *testing.T, email string) *httptest.Server {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		w.Header().Set("Metadata-Flavor", "Google")
+
+		switch r.URL.Path {
+
+		case "/computeMetadata/v1/instance/service-accounts/default/identity":
+			fmt.Fprint(w, "identity-token")
+
+		case "/computeMetadata/v1/instance/service-accounts/default/email":
+			fmt.Fprint(w, email)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	u, _ := url.Parse(server.URL)
+	t.Setenv("GCE_METADATA_HOST", u.Host)
+
+	return server
+}
+
+func newClient(t *testing.T, server *prismatest.Server) *token_gcp.Client {
+
+	t.Helper()
+
+	client, err := token_gcp.NewConfig().
+		SetAPI(server.URL).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	return client
+}
+
+func TestInvalidate(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	metadata := newMetadataServer(t, "test@example.iam.gserviceaccount.com")
+	defer metadata.Close()
+
+	client := newClient(t, server)
+
+	first, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	accountID, err := client.AccountID(context.Background())
+	if err != nil || accountID != "123456789012" {
+		t.Errorf("AccountID returned %q, %v", accountID, err)
+	}
+
+	client.Invalidate()
+
+	second, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token after Invalidate: %v", err)
+	}
+
+	if second == first {
+		t.Errorf("Token after Invalidate returned the invalidated token")
+	}
+
+	if n := server.Count("/issue"); n != 2 {
+		t.Errorf("/issue was called %d times, want 2", n)
+	}
+}
+
+func TestCacheKeyedOnServiceAccount(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	cache, err := token_cache.NewConfig().
+		SetDir(t.TempDir()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// Each client stands for a process that shares the cache
+	token := func(email string) string {
+
+		metadata := newMetadataServer(t, email)
+		defer metadata.Close()
+
+		client, err := token_gcp.NewConfig().
+			SetAPI(server.URL).
+			SetCache(cache).
+			Build()
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+
+		token, err := client.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+
+		return token
+	}
+
+	first := token("first@example.iam.gserviceaccount.com")
+
+	if token("first@example.iam.gserviceaccount.com") != first {
+		t.Errorf("token of the same service account was not read from the cache")
+	}
+
+	if token("second@example.iam.gserviceaccount.com") == first {
+		t.Errorf("token of another service account was read from the cache")
+	}
+
+	if n := server.Count("/issue"); n != 2 {
+		t.Errorf("/issue was called %d times, want 2", n)
+	}
+}
@@ -0,0 +1,348 @@
+package token
+
+/*
+
+This implements the TokenProvider Interface by wrapping another TokenProvider and renewing its
+token in a background goroutine before it expires. Callers get the current token without
+waiting for the metadata server or /issue as long as the renewals succeed.
+
+The token is renewed after RefreshFraction of its lifetime (from iat to exp) and at the latest
+Skew before exp. A token within Skew of its expiry is never returned; if the background renewal
+has not succeeded by then Token renews synchronously. Failed renewals are retried after
+RetryInterval.
+
+Close stops the background goroutine.
+
+*/
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+)
+
+// Client the token client. It is safe for concurrent use.
+type Client struct {
+	tokenProvider   TokenProvider
+	refreshFraction float64
+	skew            time.Duration
+	retryInterval   time.Duration
+	logger          *zap.Logger
+
+	mutex       sync.Mutex
+	token       string
+	iat         time.Time
+	exp         time.Time
+	retryAt     time.Time
+	invalidated bool
+
+	refreshMutex common.Mutex
+	wake         chan struct{}
+	cancel       context.CancelFunc
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// NewClient returns new Client and starts the background renewal
+func NewClient(config *Config) (*Client, error) {
+
+	logger := config.GetLogger()
+
+	logger.Debug("entering NewClient")
+
+	var errors *multierror.Error
+
+	if config.TokenProvider == nil {
+		errors = multierror.Append(errors, fmt.Errorf("interface TokenProvider is required"))
+	}
+
+	if config.RefreshFraction < 0 || config.RefreshFraction >= 1 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute RefreshFraction must be between 0 and 1"))
+	}
+
+	if config.Skew < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute Skew must not be negative"))
+	}
+
+	if config.RetryInterval < 0 {
+		errors = multierror.Append(errors, fmt.Errorf("attribute RetryInterval must not be negative"))
+	}
+
+	err := errors.ErrorOrNil()
+	if err != nil {
+		logger.Debug("returning NewClient with error(s)")
+		return nil, err
+	}
+
+	client := &Client{
+		tokenProvider:   config.TokenProvider,
+		refreshFraction: config.RefreshFraction,
+		skew:            config.Skew,
+		retryInterval:   config.RetryInterval,
+		logger:          logger,
+		wake:            make(chan struct{}, 1),
+		done:            make(chan struct{}),
+	}
+
+	if client.refreshFraction == 0 {
+		client.refreshFraction = DefaultRefreshFraction
+	}
+
+	if client.skew == 0 {
+		client.skew = DefaultSkew
+	}
+
+	if client.retryInterval == 0 {
+		client.retryInterval = DefaultRetryInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client.cancel = cancel
+
+	go client.run(ctx)
+
+	logger.Debug("returning NewClient")
+	return client, nil
+}
+
+// Token returns the current token. The token is renewed synchronously if the background renewal
+// has not provided a token that is valid for longer than Skew.
+func (t *Client) Token(ctx context.Context) (string, error) {
+
+	if token, ok := t.current(); ok {
+		return token, nil
+	}
+
+	t.logger.Debug("no valid token; renewing synchronously")
+
+	token, err := t.refresh(ctx, false)
+	if err != nil {
+		return "", err
+	}
+
+	t.signal()
+
+	return token, nil
+}
+
+// AccountID returns the account ID of the wrapped TokenProvider
+func (t *Client) AccountID(ctx context.Context) (string, error) {
+	return t.tokenProvider.AccountID(ctx)
+}
+
+// Claims returns the claims of the current token
+func (t *Client) Claims(ctx context.Context) (*common.Claims, error) {
+
+	token, err := t.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.ParseToken(token)
+}
+
+// Invalidate discards the current token so that the next call to Token renews it. The wrapped
+// TokenProvider is invalidated before that renewal if it implements Invalidator.
+func (t *Client) Invalidate() {
+
+	t.mutex.Lock()
+	t.token = ""
+	t.retryAt = time.Time{}
+	t.invalidated = true
+	t.mutex.Unlock()
+
+	t.logger.Debug("token invalidated")
+
+	t.signal()
+}
+
+// Close stops the background renewal and waits for it to return. The client may still be used
+// afterwards; tokens are then renewed synchronously.
+func (t *Client) Close() error {
+
+	t.closeOnce.Do(func() {
+		t.cancel()
+		<-t.done
+	})
+
+	return nil
+}
+
+func (t *Client) run(ctx context.Context) {
+
+	defer close(t.done)
+
+	for {
+
+		timer := time.NewTimer(t.nextRefresh())
+
+		select {
+
+		case <-ctx.Done():
+			timer.Stop()
+			return
+
+		case <-t.wake:
+			timer.Stop()
+			continue
+
+		case <-timer.C:
+		}
+
+		_, err := t.refresh(ctx, true)
+		if err != nil && ctx.Err() == nil {
+			t.logger.Warn("background token renewal failed", zap.Duration("retryIn", t.retryInterval), zap.Error(err))
+		}
+	}
+}
+
+// refresh renews the token unless another caller already did while waiting for the refresh
+// mutex. The background renewal renews when the token is due; Token renews when the token is not
+// valid.
+func (t *Client) refresh(ctx context.Context, background bool) (string, error) {
+
+	err := t.refreshMutex.LockContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer t.refreshMutex.Unlock()
+
+	if !background {
+		if token, ok := t.current(); ok {
+			return token, nil
+		}
+	} else if t.nextRefresh() > 0 {
+		return "", nil
+	}
+
+	token, err := t.renew(ctx)
+	if err == nil {
+		var iat, exp time.Time
+		iat, exp, err = parseTimes(token)
+		if err == nil {
+			t.store(token, iat, exp)
+			t.logger.Debug("token renewed", zap.Time("exp", exp))
+			return token, nil
+		}
+	}
+
+	t.mutex.Lock()
+	t.retryAt = time.Now().Add(t.retryInterval)
+	t.mutex.Unlock()
+
+	return "", err
+}
+
+// renew returns a new token from the wrapped TokenProvider. The wrapped provider is only
+// invalidated after Invalidate, since that evicts its token from a shared cache; otherwise it is
+// refreshed if it implements Refresher.
+func (t *Client) renew(ctx context.Context) (string, error) {
+
+	t.mutex.Lock()
+	invalidated := t.invalidated
+	t.invalidated = false
+	t.mutex.Unlock()
+
+	refresher, ok := t.tokenProvider.(Refresher)
+	if ok && !invalidated {
+		return refresher.Refresh(ctx)
+	}
+
+	if invalidator, ok := t.tokenProvider.(Invalidator); ok {
+		invalidator.Invalidate()
+	}
+
+	return t.tokenProvider.Token(ctx)
+}
+
+func (t *Client) store(token string, iat, exp time.Time) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.token = token
+	t.iat = iat
+	t.exp = exp
+	t.retryAt = time.Time{}
+
+	// A provider that can not be made to renew returns the same token; wait before asking again
+	if !time.Now().Before(t.refreshAt()) {
+		t.retryAt = time.Now().Add(t.retryInterval)
+	}
+}
+
+// current returns the token if it is valid for longer than skew
+func (t *Client) current() (string, bool) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.token == "" || !time.Now().Before(t.exp.Add(-t.skew)) {
+		return "", false
+	}
+
+	return t.token, true
+}
+
+// nextRefresh returns the delay until the next background renewal
+func (t *Client) nextRefresh() time.Duration {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if !t.retryAt.IsZero() {
+		return time.Until(t.retryAt)
+	}
+
+	if t.token == "" {
+		return 0
+	}
+
+	return time.Until(t.refreshAt())
+}
+
+// refreshAt returns the time the token is due for renewal; it must be called with the mutex held
+func (t *Client) refreshAt() time.Time {
+
+	lifetime := t.exp.Sub(t.iat)
+	refreshAt := t.iat.Add(time.Duration(float64(lifetime) * t.refreshFraction))
+
+	if latest := t.exp.Add(-t.skew); refreshAt.After(latest) {
+		return latest
+	}
+
+	return refreshAt
+}
+
+func (t *Client) signal() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// parseTimes returns the iat and exp claims of a JWT. If iat is missing the current time is used.
+func parseTimes(token string) (time.Time, time.Time, error) {
+
+	claims, err := common.ParseToken(token)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if claims.Exp == 0 {
+		return time.Time{}, time.Time{}, fmt.Errorf("token has no exp claim")
+	}
+
+	iat := claims.IssuedAt()
+	if claims.Iat == 0 {
+		iat = time.Now()
+	}
+
+	return iat, claims.ExpiresAt(), nil
+}
@@ -0,0 +1,353 @@
+package token_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aporeto-se/prisma-sdk-go-v2/prismatest"
+	token_envvars "github.com/aporeto-se/prisma-sdk-go-v2/token/aws/envvars"
+	token_cache "github.com/aporeto-se/prisma-sdk-go-v2/token/cache"
+	"github.com/aporeto-se/prisma-sdk-go-v2/token/common"
+	token_refresh "github.com/aporeto-se/prisma-sdk-go-v2/token/refresh"
+)
+
+// testProvider wraps a provider that issues short lived tokens from prismatest. Token and Refresh
+// fail while failing is set and wait for the context to be done while blocking is set.
+type testProvider struct {
+	*token_envvars.Client
+	calls    int32
+	failing  int32
+	blocking int32
+}
+
+func (t *testProvider) Token(ctx context.Context) (string, error) {
+
+	err := t.call(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return t.Client.Token(ctx)
+}
+
+func (t *testProvider) Refresh(ctx context.Context) (string, error) {
+
+	err := t.call(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return t.Client.Refresh(ctx)
+}
+
+func (t *testProvider) call(ctx context.Context) error {
+
+	atomic.AddInt32(&t.calls, 1)
+
+	if atomic.LoadInt32(&t.blocking) != 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	if atomic.LoadInt32(&t.failing) != 0 {
+		return fmt.Errorf("renewal failed")
+	}
+
+	return nil
+}
+
+// newProvider returns a testProvider whose tokens are valid for validity. The claims have a
+// resolution of one second, so the tests use lifetimes of a few seconds and renew at least a
+// second after the token was issued.
+func newProvider(t *testing.T, server *prismatest.Server, validity time.Duration) *testProvider {
+
+	t.Helper()
+
+	client, err := token_envvars.NewConfig().
+		SetAPI(server.URL).
+		SetAccessKeyID("AKIAEXAMPLE").
+		SetSecretAccessKey("secret").
+		SetSessionToken("session").
+		SetValidity(validity).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	return &testProvider{Client: client}
+}
+
+func expiresAt(t *testing.T, token string) time.Time {
+
+	t.Helper()
+
+	claims, err := common.ParseToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return claims.ExpiresAt()
+}
+
+func TestRenewsBeforeExpiry(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	provider := newProvider(t, server, 4*time.Second)
+
+	client, err := token_refresh.NewConfig().
+		SetTokenProvider(provider).
+		SetRefreshFraction(0.4).
+		SetSkew(time.Second).
+		SetRetryInterval(time.Minute).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	defer client.Close()
+
+	first, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	// Token renews synchronously only within Skew of the expiry; a new token before then comes
+	// from the background renewal
+	deadline := expiresAt(t, first).Add(-time.Second)
+
+	for time.Now().Before(deadline) {
+
+		token, err := client.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token: %v", err)
+		}
+
+		if token != first {
+			if !expiresAt(t, token).After(expiresAt(t, first)) {
+				t.Errorf("renewed token does not expire later than the first")
+			}
+			return
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("token was not renewed in the background before expiry")
+}
+
+func TestSynchronousRenewalAfterBackgroundFailure(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	provider := newProvider(t, server, 4*time.Second)
+
+	client, err := token_refresh.NewConfig().
+		SetTokenProvider(provider).
+		SetRefreshFraction(0.4).
+		SetSkew(2 * time.Second).
+		SetRetryInterval(time.Minute).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	defer client.Close()
+
+	first, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	// Let the background renewal fail; it is not retried before RetryInterval
+	calls := atomic.LoadInt32(&provider.calls)
+	atomic.StoreInt32(&provider.failing, 1)
+
+	for atomic.LoadInt32(&provider.calls) == calls {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	atomic.StoreInt32(&provider.failing, 0)
+
+	time.Sleep(time.Until(expiresAt(t, first).Add(-2 * time.Second)))
+
+	token, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	if token == first {
+		t.Errorf("Token returned a token within Skew of its expiry")
+	}
+}
+
+func TestCloseStopsRenewal(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	provider := newProvider(t, server, time.Minute)
+	atomic.StoreInt32(&provider.blocking, 1)
+
+	client, err := token_refresh.NewConfig().
+		SetTokenProvider(provider).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	// Without a token the background renewal starts at once and blocks in the provider
+	for atomic.LoadInt32(&provider.calls) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		client.Close()
+		client.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return")
+	}
+
+	// The client still renews synchronously after Close
+	atomic.StoreInt32(&provider.blocking, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.Token(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("Token after Close did not return")
+	}
+	if err != nil {
+		t.Fatalf("Token after Close: %v", err)
+	}
+}
+
+func TestRenewalBypassesCache(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	cache, err := token_cache.NewConfig().
+		SetDir(t.TempDir()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	provider, err := token_envvars.NewConfig().
+		SetAPI(server.URL).
+		SetAccessKeyID("AKIAEXAMPLE").
+		SetSecretAccessKey("secret").
+		SetSessionToken("session").
+		SetCache(cache).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	client, err := token_refresh.NewConfig().
+		SetTokenProvider(provider).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	defer client.Close()
+
+	first, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	// The renewal after Invalidate invalidates the wrapped provider, which must not return the
+	// cached token
+	client.Invalidate()
+
+	token, err := client.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	if token == first {
+		t.Errorf("renewal returned the cached token")
+	}
+}
+
+// deleteCounter counts the entries evicted from a token cache
+type deleteCounter struct {
+	*token_cache.Client
+	deleted int32
+}
+
+func (t *deleteCounter) Delete(ctx context.Context, key string) error {
+	atomic.AddInt32(&t.deleted, 1)
+	return t.Client.Delete(ctx, key)
+}
+
+func TestRenewalKeepsCache(t *testing.T) {
+
+	server := prismatest.NewServer("/tenant")
+	defer server.Close()
+
+	client, err := token_cache.NewConfig().
+		SetDir(t.TempDir()).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	cache := &deleteCounter{Client: client}
+
+	provider, err := token_envvars.NewConfig().
+		SetAPI(server.URL).
+		SetAccessKeyID("AKIAEXAMPLE").
+		SetSecretAccessKey("secret").
+		SetSessionToken("session").
+		SetValidity(4 * time.Second).
+		SetCache(cache).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	refresh, err := token_refresh.NewConfig().
+		SetTokenProvider(provider).
+		SetRefreshFraction(0.4).
+		SetSkew(time.Second).
+		SetRetryInterval(time.Minute).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	defer refresh.Close()
+
+	first, err := refresh.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	deadline := expiresAt(t, first).Add(-time.Second)
+
+	for server.Count("/issue") < 2 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if n := server.Count("/issue"); n != 2 {
+		t.Fatalf("/issue was called %d times before expiry, want 2", n)
+	}
+
+	// The token other processes may still use is not evicted by a proactive renewal
+	if n := atomic.LoadInt32(&cache.deleted); n != 0 {
+		t.Errorf("renewal evicted %d cache entries, want 0", n)
+	}
+}
@@ -0,0 +1,103 @@
+package token
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRefreshFraction is the default fraction of the token lifetime after which the token
+	// is renewed
+	DefaultRefreshFraction = 0.8
+
+	// DefaultSkew is the default margin before expiry after which a token is no longer used
+	DefaultSkew = 30 * time.Second
+
+	// DefaultRetryInterval is the default delay before a failed renewal is retried
+	DefaultRetryInterval = 10 * time.Second
+)
+
+// TokenProvider is the wrapped token provider. It has the same methods as the TokenProvider of
+// the api package.
+type TokenProvider interface {
+	Token(context.Context) (string, error)
+	AccountID(ctx context.Context) (string, error)
+}
+
+// Refresher is optionally implemented by the wrapped TokenProvider. Refresh is called to renew the
+// token before it expires; it returns a new token without evicting the current one from a shared
+// cache.
+type Refresher interface {
+	Refresh(context.Context) (string, error)
+}
+
+// Invalidator is optionally implemented by the wrapped TokenProvider. It is called before the
+// renewal that follows a call to Invalidate, and before every renewal if the wrapped provider is
+// not a Refresher, so that the wrapped provider does not return its current token.
+type Invalidator interface {
+	Invalidate()
+}
+
+// Config config
+type Config struct {
+	TokenProvider   TokenProvider
+	RefreshFraction float64
+	Skew            time.Duration
+	RetryInterval   time.Duration
+	Logger          *zap.Logger
+}
+
+// NewConfig returns new Config
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// SetTokenProvider sets interface and returns self
+func (t *Config) SetTokenProvider(tokenProvider TokenProvider) *Config {
+	t.TokenProvider = tokenProvider
+	return t
+}
+
+// SetRefreshFraction sets the fraction (0 to 1) of the token lifetime after which the token is
+// renewed and returns self. If not set DefaultRefreshFraction is used.
+func (t *Config) SetRefreshFraction(refreshFraction float64) *Config {
+	t.RefreshFraction = refreshFraction
+	return t
+}
+
+// SetSkew sets the margin before expiry after which a token is no longer used and returns self.
+// It allows for clock differences between this host and the API. If not set DefaultSkew is used.
+func (t *Config) SetSkew(skew time.Duration) *Config {
+	t.Skew = skew
+	return t
+}
+
+// SetRetryInterval sets the delay before a failed renewal is retried and returns self. If not set
+// DefaultRetryInterval is used.
+func (t *Config) SetRetryInterval(retryInterval time.Duration) *Config {
+	t.RetryInterval = retryInterval
+	return t
+}
+
+// SetLogger sets entity and returns self. If not set nothing is logged.
+func (t *Config) SetLogger(logger *zap.Logger) *Config {
+	t.Logger = logger
+	return t
+}
+
+// GetLogger returns entity. If entity is nil a no-op logger is returned.
+func (t *Config) GetLogger() *zap.Logger {
+
+	if t.Logger == nil {
+		return zap.NewNop()
+	}
+
+	return t.Logger
+}
+
+// Build returns entity
+func (t *Config) Build() (*Client, error) {
+	return NewClient(t)
+}
@@ -30,6 +30,8 @@ func TrafficActionFromString(s string) (TrafficAction, error) {
 		return TrafficActionAllow, nil
 	case "REJECT":
 		return TrafficActionReject, nil
+	case "INHERIT":
+		return TrafficActionInherit, nil
 	}
 
 	return TrafficActionUndefined, fmt.Errorf("String %s is not a valid TrafficAction type", s)
@@ -65,3 +67,137 @@ func NamespaceTypeFromString(s string) (NamespaceType, error) {
 
 	return NamespaceTypeUndefined, fmt.Errorf("String %s is not a valid NamespaceType type", s)
 }
+
+// ================================================================================================
+
+// Role is a Prisma role that can be granted to a subject with the authorizedIdentities attribute of
+// an APIAuthorizationPolicy
+type Role string
+
+const (
+	// RoleNamespaceAdministrator has full access to the namespace and its children
+	RoleNamespaceAdministrator Role = "@auth:role=namespace.administrator"
+	// RoleNamespaceEditor can create, update and delete most objects in the namespace
+	RoleNamespaceEditor Role = "@auth:role=namespace.editor"
+	// RoleNamespaceContributor can create and update objects but not delete them
+	RoleNamespaceContributor Role = "@auth:role=namespace.contributor"
+	// RoleNamespaceViewer has read only access to the namespace
+	RoleNamespaceViewer Role = "@auth:role=namespace.viewer"
+	// RoleNamespaceAuditor has read only access to the namespace including logs and reports
+	RoleNamespaceAuditor Role = "@auth:role=namespace.auditor"
+	// RoleEnforcer is the role used by enforcers to register and operate
+	RoleEnforcer Role = "@auth:role=enforcer"
+	// RoleEnforcerRuntime is the role used by enforcers once registered
+	RoleEnforcerRuntime Role = "@auth:role=enforcer.runtime"
+)
+
+// Roles returns all the known Roles
+func Roles() []Role {
+	return []Role{
+		RoleNamespaceAdministrator,
+		RoleNamespaceEditor,
+		RoleNamespaceContributor,
+		RoleNamespaceViewer,
+		RoleNamespaceAuditor,
+		RoleEnforcer,
+		RoleEnforcerRuntime,
+	}
+}
+
+// RoleFromString returns type Role from string. The string may be either the full identity
+// (@auth:role=namespace.viewer) or only the role name (namespace.viewer). If the string is not a
+// known role an error will returned.
+func RoleFromString(s string) (Role, error) {
+
+	name := strings.TrimPrefix(strings.ToLower(s), "@auth:role=")
+
+	for _, v := range Roles() {
+		if v.Name() == name {
+			return v, nil
+		}
+	}
+
+	return "", fmt.Errorf("String %s is not a valid Role type", s)
+}
+
+// Name returns the name of the role without the @auth:role= prefix
+func (t Role) Name() string {
+	return strings.TrimPrefix(string(t), "@auth:role=")
+}
+
+// ================================================================================================
+
+// Identity is the identity of a kind of Prisma object. Permissions on the objects of an identity can
+// be granted with the authorizedIdentities attribute of an APIAuthorizationPolicy.
+type Identity string
+
+const (
+	// IdentityNamespace is the identity of namespaces
+	IdentityNamespace Identity = "namespace"
+	// IdentityExternalNetwork is the identity of external networks
+	IdentityExternalNetwork Identity = "externalnetwork"
+	// IdentityNetworkRuleSetPolicy is the identity of network rule set policies
+	IdentityNetworkRuleSetPolicy Identity = "networkrulesetpolicy"
+	// IdentityAPIAuthorizationPolicy is the identity of API authorization policies
+	IdentityAPIAuthorizationPolicy Identity = "apiauthorizationpolicy"
+	// IdentityAppCredential is the identity of app credentials
+	IdentityAppCredential Identity = "appcredential"
+	// IdentityImport is the identity of imports
+	IdentityImport Identity = "import"
+	// IdentityExport is the identity of exports
+	IdentityExport Identity = "export"
+)
+
+// Identities returns all the known Identities
+func Identities() []Identity {
+	return []Identity{
+		IdentityNamespace,
+		IdentityExternalNetwork,
+		IdentityNetworkRuleSetPolicy,
+		IdentityAPIAuthorizationPolicy,
+		IdentityAppCredential,
+		IdentityImport,
+		IdentityExport,
+	}
+}
+
+// IdentityFromString returns type Identity from string. If the string is not a known identity an
+// error will returned.
+func IdentityFromString(s string) (Identity, error) {
+
+	for _, v := range Identities() {
+		if string(v) == strings.ToLower(s) {
+			return v, nil
+		}
+	}
+
+	return "", fmt.Errorf("String %s is not a valid Identity type", s)
+}
+
+// ================================================================================================
+
+// Operation is an operation on Prisma objects
+type Operation string
+
+const (
+	// OperationGet reads objects
+	OperationGet Operation = "get"
+	// OperationPost creates objects
+	OperationPost Operation = "post"
+	// OperationPut updates objects
+	OperationPut Operation = "put"
+	// OperationDelete deletes objects
+	OperationDelete Operation = "delete"
+)
+
+// Permission returns the authorized identity that grants the operations on the objects of the
+// identity, for example externalnetwork:get,put
+func (t Identity) Permission(operation ...Operation) string {
+
+	var operations []string
+	for _, v := range operation {
+		operations = append(operations, string(v))
+	}
+
+	return string(t) + ":" + strings.Join(operations, ",")
+}
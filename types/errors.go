@@ -2,6 +2,8 @@ package types
 
 import (
 	"encoding/json"
+	"net/http"
+	"strings"
 )
 
 // APIError is one of the following
@@ -61,15 +63,32 @@ func (m *APIError) Error() string {
 	return string(m.Description)
 }
 
-// NewAPIError returns a new APIError from a byte slice
+// IsTemporary returns true if the error is a temporary failure that may be retried (423, 429,
+// 502, 503 and 504)
+func (m *APIError) IsTemporary() bool {
+	switch m.Code {
+	case 423, 429, 502, 503, 504:
+		return true
+	}
+	return false
+}
+
+// NewAPIError returns a new APIError from a byte slice. If the byte slice is not a Prisma API
+// error the description is set to the byte slice.
 func NewAPIError(input []byte) *APIError {
 	var raw *DataAPIError
 	json.Unmarshal(input, &raw)
 
 	e := &APIError{}
 
+	if raw == nil {
+		e.Description = strings.TrimSpace(string(input))
+		return e
+	}
+
 	for _, v := range *raw {
 		e.Code = v.Code
+		e.Data.Attribute = v.Data.Attribute
 		e.Description = v.Description
 		e.Subject = v.Subject
 		e.Title = v.Title
@@ -79,6 +98,23 @@ func NewAPIError(input []byte) *APIError {
 	return e
 }
 
+// NewAPIErrorWithCode returns a new APIError from a byte slice. The code is set to statusCode if
+// it is not set in the byte slice; this is the case for errors returned by proxies and gateways.
+func NewAPIErrorWithCode(statusCode int, input []byte) *APIError {
+
+	e := NewAPIError(input)
+
+	if e.Code == 0 {
+		e.Code = statusCode
+	}
+
+	if e.Description == "" {
+		e.Description = http.StatusText(statusCode)
+	}
+
+	return e
+}
+
 // TokenExpiredError is an Token Expired Error
 type TokenExpiredError struct {
 	Message string
@@ -1,7 +1,10 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"time"
 )
 
 const (
@@ -16,6 +19,7 @@ const (
 // namespace scheme makes it easier to provide a good user experience and control access.
 type Namespace struct {
 	Name                           string              `json:"name,omitempty" yaml:"name,omitempty"`
+	Description                    string              `json:"description,omitempty" yaml:"description,omitempty"`
 	NamespaceType                  NamespaceType       `json:"namespaceType,omitempty" yaml:"namespaceType,omitempty"`
 	DefaultPUIncomingTrafficAction TrafficAction       `json:"defaultPUIncomingTrafficAction,omitempty" yaml:"defaultPUIncomingTrafficAction,omitempty"`
 	DefaultPUOutgoingTrafficAction TrafficAction       `json:"defaultPUOutgoingTrafficAction,omitempty" yaml:"defaultPUOutgoingTrafficAction,omitempty"`
@@ -32,6 +36,12 @@ func NewNamespace(name string) *Namespace {
 	}
 }
 
+// SetDescription sets Description and returns self
+func (t *Namespace) SetDescription(v string) *Namespace {
+	t.Description = v
+	return t
+}
+
 // SetNamespaceType sets NamespaceType and returns self
 func (t *Namespace) SetNamespaceType(v NamespaceType) *Namespace {
 	t.NamespaceType = v
@@ -110,8 +120,16 @@ func (t *Namespace) AddTagPrefixs(v ...string) *Namespace {
 // APIAuthorizationPolicy (API authorization) defines the operations a user can perform in a
 // namespace: GET, POST, PUT, DELETE, PATCH, and/or HEAD. It is also possible to restrict the user
 // to a subset of the APIs in the namespace by setting authorizedIdentities. An API authorization
-// always propagates down to all the children of the current namespace.
+// always propagates down to all the children of the current namespace. The attributes ID,
+// Namespace, CreateTime, UpdateTime, ImportHash and ImportLabel are populated by the server and are
+// ignored on create and update.
 type APIAuthorizationPolicy struct {
+	ID                   string      `json:"ID,omitempty" yaml:"ID,omitempty"`
+	Namespace            string      `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	CreateTime           *time.Time  `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	UpdateTime           *time.Time  `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+	ImportHash           string      `json:"importHash,omitempty" yaml:"importHash,omitempty"`
+	ImportLabel          string      `json:"importLabel,omitempty" yaml:"importLabel,omitempty"`
 	Name                 string      `json:"name,omitempty" yaml:"name,omitempty"`
 	Description          string      `json:"description,omitempty" yaml:"description,omitempty"`
 	Protected            bool        `json:"protected" yaml:"protected"`
@@ -184,9 +202,19 @@ func (t *APIAuthorizationPolicy) SetAuthorizedIdentities(authorizedIdentities []
 	return t
 }
 
-// AddAuthorizedIdentity sets authorizedIdentity and returns self
-func (t *APIAuthorizationPolicy) AddAuthorizedIdentity(authorizedIdentity string) *APIAuthorizationPolicy {
-	t.AuthorizedIdentities = append(t.AuthorizedIdentities, authorizedIdentity)
+// AddAuthorizedIdentity adds the roles to authorizedIdentities and returns self. Permissions on
+// identities are added with AddPermission.
+func (t *APIAuthorizationPolicy) AddAuthorizedIdentity(authorizedIdentity ...Role) *APIAuthorizationPolicy {
+	for _, v := range authorizedIdentity {
+		t.AuthorizedIdentities = append(t.AuthorizedIdentities, string(v))
+	}
+	return t
+}
+
+// AddPermission adds the permission for the operations on the objects of identity to
+// authorizedIdentities and returns self
+func (t *APIAuthorizationPolicy) AddPermission(identity Identity, operation ...Operation) *APIAuthorizationPolicy {
+	t.AuthorizedIdentities = append(t.AuthorizedIdentities, identity.Permission(operation...))
 	return t
 }
 
@@ -201,8 +229,16 @@ func (t *APIAuthorizationPolicy) SetAuthorizedNamespace(authorizedNamespace stri
 // to the declared network or IP, using the provided protocol and port (or range of ports). If you
 // want to describe the internet (i.e., anywhere), use 0.0.0.0/0 as the address and 1-65000 for the
 // ports. You must assign the external network one or more tags. These allow you to reference the
-// external network from your network policies.
+// external network from your network policies. The attributes ID, Namespace, CreateTime,
+// UpdateTime, ImportHash and ImportLabel are populated by the server and are ignored on create and
+// update.
 type Externalnetwork struct {
+	ID             string      `json:"ID,omitempty" yaml:"ID,omitempty"`
+	Namespace      string      `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	CreateTime     *time.Time  `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	UpdateTime     *time.Time  `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+	ImportHash     string      `json:"importHash,omitempty" yaml:"importHash,omitempty"`
+	ImportLabel    string      `json:"importLabel,omitempty" yaml:"importLabel,omitempty"`
 	Name           string      `json:"name,omitempty" yaml:"name,omitempty"`
 	Description    string      `json:"description,omitempty" yaml:"description,omitempty"`
 	Protected      bool        `json:"protected" yaml:"protected"`
@@ -267,8 +303,16 @@ func (t *Externalnetwork) AddEntry(entry string) *Externalnetwork {
 	return t
 }
 
-// Networkrulesetpolicy Prisma network rule set policy
+// Networkrulesetpolicy Prisma network rule set policy. The attributes ID, Namespace, CreateTime,
+// UpdateTime, ImportHash and ImportLabel are populated by the server and are ignored on create
+// and update.
 type Networkrulesetpolicy struct {
+	ID             string      `json:"ID,omitempty" yaml:"ID,omitempty"`
+	Namespace      string      `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	CreateTime     *time.Time  `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	UpdateTime     *time.Time  `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+	ImportHash     string      `json:"importHash,omitempty" yaml:"importHash,omitempty"`
+	ImportLabel    string      `json:"importLabel,omitempty" yaml:"importLabel,omitempty"`
 	Description    string      `json:"description,omitempty" yaml:"description,omitempty"`
 	Name           string      `json:"name,omitempty" yaml:"name,omitempty"`
 	IncomingRules  []*Rule     `json:"incomingRules,omitempty" yaml:"incomingRules,omitempty"`
@@ -507,3 +551,111 @@ func (t *PrismaConfig) AddNetworkrulesetpolicy(networkrulesetpolicy *Networkrule
 type PrismaConfigOuter struct {
 	Data *PrismaConfig `json:"data,omitempty" yaml:"data,omitempty"`
 }
+
+// Credential is an app credential file as created by Prisma. It holds everything a service needs to
+// authenticate with mutual TLS: the client certificate and key, the CA of the API and the API URL.
+// Certificate, CertificateKey and CertificateAuthority are base64 encoded PEM.
+type Credential struct {
+	ID                   string `json:"ID,omitempty" yaml:"ID,omitempty"`
+	Name                 string `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace            string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	APIURL               string `json:"APIURL,omitempty" yaml:"APIURL,omitempty"`
+	Certificate          string `json:"certificate,omitempty" yaml:"certificate,omitempty"`
+	CertificateAuthority string `json:"certificateAuthority,omitempty" yaml:"certificateAuthority,omitempty"`
+	CertificateKey       string `json:"certificateKey,omitempty" yaml:"certificateKey,omitempty"`
+}
+
+// NewCredentialFromFile returns the Credential read from the app credential file at path
+func NewCredentialFromFile(path string) (*Credential, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *Credential
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("credential file %s is invalid: %w", path, err)
+	}
+
+	return result, nil
+}
+
+// WriteFile writes the Credential as an app credential file to path. The file is only readable
+// by the owner as it holds the private key.
+func (t *Credential) WriteFile(path string) error {
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// AppCredential (App credential) is an identity for a service that is not a user. It grants the
+// roles in the namespace and can be restricted to subnets. When an app credential is created the
+// server returns the credential file in Credentials; it is not returned by list and get. The
+// attributes ID, Namespace, CreateTime and UpdateTime are populated by the server and are ignored
+// on create and update.
+type AppCredential struct {
+	ID                string      `json:"ID,omitempty" yaml:"ID,omitempty"`
+	Namespace         string      `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	CreateTime        *time.Time  `json:"createTime,omitempty" yaml:"createTime,omitempty"`
+	UpdateTime        *time.Time  `json:"updateTime,omitempty" yaml:"updateTime,omitempty"`
+	Name              string      `json:"name,omitempty" yaml:"name,omitempty"`
+	Description       string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Disabled          bool        `json:"disabled" yaml:"disabled"`
+	Protected         bool        `json:"protected" yaml:"protected"`
+	AssociatedTags    []string    `json:"associatedTags,omitempty" yaml:"associatedTags,omitempty"`
+	Roles             []string    `json:"roles,omitempty" yaml:"roles,omitempty"`
+	AuthorizedSubnets []string    `json:"authorizedSubnets,omitempty" yaml:"authorizedSubnets,omitempty"`
+	Credentials       *Credential `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+}
+
+// NewAppCredential returns a new AppCredential with specified name
+func NewAppCredential(name string) *AppCredential {
+	return &AppCredential{
+		Name: name,
+	}
+}
+
+// SetDescription sets description and returns self
+func (t *AppCredential) SetDescription(description string) *AppCredential {
+	t.Description = description
+	return t
+}
+
+// SetDisabled sets disabled and returns self
+func (t *AppCredential) SetDisabled(disabled bool) *AppCredential {
+	t.Disabled = disabled
+	return t
+}
+
+// SetProtected sets protected and returns self
+func (t *AppCredential) SetProtected(protected bool) *AppCredential {
+	t.Protected = protected
+	return t
+}
+
+// AddAssociatedTag adds associatedTag and returns self
+func (t *AppCredential) AddAssociatedTag(associatedTag string) *AppCredential {
+	t.AssociatedTags = append(t.AssociatedTags, associatedTag)
+	return t
+}
+
+// AddRole adds role and returns self
+func (t *AppCredential) AddRole(role ...Role) *AppCredential {
+	for _, v := range role {
+		t.Roles = append(t.Roles, string(v))
+	}
+	return t
+}
+
+// AddAuthorizedSubnet restricts the app credential to requests from the subnet (CIDR) and returns
+// self
+func (t *AppCredential) AddAuthorizedSubnet(authorizedSubnet ...string) *AppCredential {
+	t.AuthorizedSubnets = append(t.AuthorizedSubnets, authorizedSubnet...)
+	return t
+}